@@ -151,13 +151,26 @@ func addConfiguration(logger *zerolog.Logger, config configuration.Configuration
 	if configureMcp {
 		_ = userInterface.Output(fmt.Sprintf("\n🔧 Configuring Snyk MCP for %s...\n", ideConf.name))
 
+		transport := config.GetString(shared.TransportParam)
 		cmd, args := determineCommand(cliPath, config.GetString(configuration.INTEGRATION_NAME))
 		env := getSnykMcpEnv(config)
 
 		if ideConf.mcpGlobalConfigPath != "" {
 			_ = userInterface.Output(fmt.Sprintf("📝 Configuring MCP server at: %s", ideConf.mcpGlobalConfigPath))
 
-			err := ensureMcpServerInJson(ideConf.mcpGlobalConfigPath, shared.ServerNameKey, cmd, args, env, logger)
+			var err error
+			switch transport {
+			case "", stdioTransport:
+				err = ensureMcpServerInJson(ideConf.mcpGlobalConfigPath, shared.ServerNameKey, cmd, args, env, logger)
+			case sseTransport, httpTransport:
+				var serverURL string
+				serverURL, err = mcpServerURL(config.GetString(shared.ServerUrlParam), transport)
+				if err == nil {
+					err = ensureMcpUrlServerInJson(ideConf.mcpGlobalConfigPath, shared.ServerNameKey, transport, serverURL, getSnykMcpHeaders(config), logger)
+				}
+			default:
+				err = fmt.Errorf("invalid transport: %s. supported values are %s, %s, %s", transport, stdioTransport, sseTransport, httpTransport)
+			}
 			if err != nil {
 				return fmt.Errorf("failed to configure MCP server for %s: %w", ideConf.name, err)
 			}
@@ -166,7 +179,9 @@ func addConfiguration(logger *zerolog.Logger, config configuration.Configuration
 			logger.Info().Msgf("Successfully configured MCP server for %s at %s", ideConf.name, ideConf.mcpGlobalConfigPath)
 		}
 
-		if configureMcpCallbackFunc != nil {
+		if configureMcpCallbackFunc != nil && transport != "" && transport != stdioTransport {
+			logger.Warn().Msgf("MCP configure callback only registers stdio servers, not registering %s server for %s", transport, ideConf.name)
+		} else if configureMcpCallbackFunc != nil {
 			err := configureMcpCallbackFunc(cmd, args, env)
 			if err != nil {
 				logger.Error().Err(err).Msgf("failed to trigger MCP configure callback for %s", ideConf.name)
@@ -5,11 +5,13 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/rs/zerolog"
+	"github.com/snyk/go-application-framework/pkg/configuration"
 	"github.com/snyk/studio-mcp/shared"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -485,6 +487,108 @@ func TestEnsureMcpServerInJson(t *testing.T) {
 	})
 }
 
+func TestEnsureMcpUrlServerInJson(t *testing.T) {
+	nopLogger := zerolog.New(io.Discard)
+	logger := &nopLogger
+
+	t.Run("replaces the stdio entry with an authenticated url entry", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "mcp.json")
+		err := ensureMcpServerInJson(configPath, "Snyk", "/path/to/snyk-macos", []string{"mcp", "-t", "stdio"}, shared.McpEnvMap{"SNYK_CFG_ORG": "org"}, logger)
+		require.NoError(t, err)
+
+		err = ensureMcpUrlServerInJson(configPath, "Snyk", "http", "http://127.0.0.1:7695/mcp", map[string]string{"Authorization": "Bearer secret"}, logger)
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(configPath)
+		require.NoError(t, err)
+		var config map[string]map[string]map[string]any
+		require.NoError(t, json.Unmarshal(data, &config))
+		require.Len(t, config["mcpServers"], 1)
+		server := config["mcpServers"]["Snyk"]
+		assert.Equal(t, "http", server["type"])
+		assert.Equal(t, "http://127.0.0.1:7695/mcp", server["url"])
+		assert.Equal(t, map[string]any{"Authorization": "Bearer secret"}, server["headers"])
+		assert.NotContains(t, server, "command")
+		assert.NotContains(t, server, "env")
+		if runtime.GOOS != "windows" {
+			info, statErr := os.Stat(configPath)
+			require.NoError(t, statErr)
+			assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "the file holds the bearer token")
+		}
+	})
+
+	t.Run("url entry without token has no headers and can be removed", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "mcp.json")
+		err := ensureMcpUrlServerInJson(configPath, "Snyk", "sse", "http://127.0.0.1:7695/sse", nil, logger)
+		require.NoError(t, err)
+
+		var config McpConfig
+		data, err := os.ReadFile(configPath)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, &config))
+		assert.Contains(t, string(data), `"url": "http://127.0.0.1:7695/sse"`)
+		assert.NotContains(t, string(data), "headers")
+
+		require.NoError(t, removeMcpServerFromJson(configPath, "Snyk", logger))
+
+		data, err = os.ReadFile(configPath)
+		require.NoError(t, err)
+		config = McpConfig{}
+		require.NoError(t, json.Unmarshal(data, &config))
+		assert.Empty(t, config.McpServers)
+	})
+}
+
+func TestMcpServerURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		rawURL    string
+		transport string
+		expected  string
+		wantErr   bool
+	}{
+		{name: "default port for http", transport: httpTransport, expected: "http://127.0.0.1:7695/mcp"},
+		{name: "default port for sse", transport: sseTransport, expected: "http://127.0.0.1:7695/sse"},
+		{name: "adds the endpoint path", rawURL: "http://127.0.0.1:7700/", transport: httpTransport, expected: "http://127.0.0.1:7700/mcp"},
+		{name: "keeps a given path", rawURL: "http://127.0.0.1:7700/custom", transport: httpTransport, expected: "http://127.0.0.1:7700/custom"},
+		{name: "rejects url without host", rawURL: "127.0.0.1", transport: httpTransport, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serverURL, err := mcpServerURL(tt.rawURL, tt.transport)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, serverURL)
+		})
+	}
+}
+
+func TestGetSnykMcpHeaders(t *testing.T) {
+	t.Run("uses the configured token", func(t *testing.T) {
+		t.Setenv(shared.AuthTokenEnvVar, "from-env")
+		config := configuration.NewWithOpts()
+		config.Set(shared.AuthTokenParam, "from-flag")
+
+		assert.Equal(t, map[string]string{"Authorization": "Bearer from-flag"}, getSnykMcpHeaders(config))
+	})
+
+	t.Run("falls back to the environment", func(t *testing.T) {
+		t.Setenv(shared.AuthTokenEnvVar, "from-env")
+
+		assert.Equal(t, map[string]string{"Authorization": "Bearer from-env"}, getSnykMcpHeaders(configuration.NewWithOpts()))
+	})
+
+	t.Run("no headers without token", func(t *testing.T) {
+		t.Setenv(shared.AuthTokenEnvVar, "")
+
+		assert.Nil(t, getSnykMcpHeaders(configuration.NewWithOpts()))
+	})
+}
+
 func TestWriteLocalRules(t *testing.T) {
 	tempGitRoot := t.TempDir()
 	_, err := git.PlainInit(tempGitRoot, false)
@@ -1264,3 +1368,52 @@ func TestRemoveDelimitedBlock(t *testing.T) {
 		})
 	}
 }
+
+func TestStripFrontMatter(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "content with front matter",
+			content:  "---\nalwaysApply: true\n---\n\n# Title\n- rule\n",
+			expected: "# Title\n- rule",
+		},
+		{
+			name:     "content with windows line endings",
+			content:  "---\r\nalwaysApply: true\r\n---\r\n# Title\r\n",
+			expected: "# Title",
+		},
+		{
+			name:     "content without front matter",
+			content:  "# Title\n- rule\n",
+			expected: "# Title\n- rule",
+		},
+		{
+			name:     "unterminated front matter is kept",
+			content:  "---\nalwaysApply: true\n",
+			expected: "---\nalwaysApply: true",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, stripFrontMatter(tt.content))
+		})
+	}
+}
+
+func TestSecurityRules(t *testing.T) {
+	rules := SecurityRules()
+
+	assert.NotContains(t, rules, "alwaysApply")
+	assert.NotContains(t, rules, "model_decision")
+	for _, ruleFile := range []string{snykRulesAlwaysApply, snykRulesSmartApply} {
+		for _, line := range strings.Split(stripFrontMatter(ruleFile), "\n") {
+			assert.Contains(t, rules, strings.TrimSpace(line), "instructions of all rule files are included")
+		}
+	}
+	assert.Equal(t, 1, strings.Count(rules, "# Project security best practices"))
+	assert.Equal(t, 1, strings.Count(rules, "Repeat this process until no new issues are found."))
+}
@@ -1,6 +1,9 @@
 package configure
 
 import (
+	"os"
+	"strings"
+
 	"github.com/snyk/go-application-framework/pkg/configuration"
 	"github.com/snyk/studio-mcp/shared"
 )
@@ -24,3 +27,16 @@ func getSnykMcpEnv(config configuration.Configuration) shared.McpEnvMap {
 
 	return env
 }
+
+// getSnykMcpHeaders returns the HTTP headers an sse or http MCP server entry needs, i.e. the bearer token
+// the server was started with, if any
+func getSnykMcpHeaders(config configuration.Configuration) map[string]string {
+	authToken := strings.TrimSpace(config.GetString(shared.AuthTokenParam))
+	if authToken == "" {
+		authToken = strings.TrimSpace(os.Getenv(shared.AuthTokenEnvVar))
+	}
+	if authToken == "" {
+		return nil
+	}
+	return map[string]string{"Authorization": "Bearer " + authToken}
+}
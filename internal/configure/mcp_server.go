@@ -3,14 +3,28 @@ package configure
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/rs/zerolog"
+	"github.com/snyk/studio-mcp/internal/networking"
 	"github.com/snyk/studio-mcp/shared"
 )
 
+const (
+	stdioTransport = "stdio"
+	sseTransport   = "sse"
+	httpTransport  = "http"
+)
+
+// transportPaths are the endpoints `snyk mcp` serves the sse and http transports on
+var transportPaths = map[string]string{
+	sseTransport:  "/sse",
+	httpTransport: "/mcp",
+}
+
 type McpServer struct {
 	Command string            `json:"command"`
 	Args    []string          `json:"args"`
@@ -133,6 +147,97 @@ func ensureMcpServerInJson(filePath, serverKey, command string, args []string, e
 	return nil
 }
 
+// mcpServerURL returns the URL of the MCP server for the transport. Without a URL, the server is expected
+// on the default loopback port. A URL without path gets the endpoint path of the transport.
+func mcpServerURL(rawURL string, transport string) (string, error) {
+	if rawURL == "" {
+		rawURL = fmt.Sprintf("http://%s:%d", networking.DefaultHost, networking.DefaultPort)
+	}
+	serverURL, err := url.Parse(rawURL)
+	if err != nil || serverURL.Scheme == "" || serverURL.Host == "" {
+		return "", fmt.Errorf("invalid MCP server url: %s", rawURL)
+	}
+	if serverURL.Path == "" || serverURL.Path == "/" {
+		serverURL.Path = transportPaths[transport]
+	}
+	return serverURL.String(), nil
+}
+
+// ensureMcpUrlServerInJson creates or updates the entry of a Snyk MCP server that the tool connects to by URL,
+// i.e. one started with `snyk mcp -t sse` or `snyk mcp -t http`. An existing stdio entry of the Snyk MCP server
+// is turned into the URL entry. The headers are written as given, so the file is only readable by the user
+// if they are set, as they carry the bearer token.
+// This function preserves all other fields in the JSON file
+func ensureMcpUrlServerInJson(filePath, serverKey, transport, serverURL string, headers map[string]string, logger *zerolog.Logger) error {
+	var config map[string]interface{}
+	if data, err := os.ReadFile(filePath); err == nil {
+		if strings.TrimSpace(string(data)) == "" {
+			data = []byte("{}")
+		}
+		if err := json.Unmarshal(data, &config); err != nil {
+			return fmt.Errorf("failed to unmarshal config file: %w", err)
+		}
+	} else if os.IsNotExist(err) {
+		config = make(map[string]interface{})
+	} else {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	mcpServers, ok := config["mcpServers"].(map[string]interface{})
+	if !ok {
+		mcpServers = make(map[string]interface{})
+	}
+
+	expectedArgs := []string{shared.McpServerStdioArg1, shared.McpServerStdioArg2, shared.McpServerStdioArg3}
+	keyToUse := serverKey
+	if matchingKeys := findMatchingServerKeys(mcpServers, expectedArgs, 1); len(matchingKeys) > 0 {
+		keyToUse = matchingKeys[0]
+	}
+
+	serverMap, ok := mcpServers[keyToUse].(map[string]interface{})
+	if !ok {
+		serverMap = make(map[string]interface{})
+	}
+	delete(serverMap, "command")
+	delete(serverMap, "args")
+	delete(serverMap, "env")
+	serverMap["type"] = transport
+	serverMap["url"] = serverURL
+	if len(headers) > 0 {
+		serverMap["headers"] = headers
+	} else {
+		delete(serverMap, "headers")
+	}
+
+	mcpServers[keyToUse] = serverMap
+	config["mcpServers"] = mcpServers
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	perm := os.FileMode(0644)
+	if len(headers) > 0 {
+		perm = 0600
+	}
+	if err := os.WriteFile(filePath, data, perm); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	// WriteFile keeps the permissions of an existing file
+	if len(headers) > 0 {
+		if err := os.Chmod(filePath, perm); err != nil {
+			logger.Warn().Err(err).Msgf("Unable to restrict permissions of %s", filePath)
+		}
+	}
+
+	return nil
+}
+
 // removeMcpServerFromJson removes an MCP server from the configuration JSON file
 // This function identifies the SAI MCP server by its command and args.
 // It only removes if exactly one server with the matching command and args is found.
@@ -172,6 +277,10 @@ func removeMcpServerFromJson(filePath, serverKey string, logger *zerolog.Logger)
 	// Look for any server where command contains McpServerCommand and args are ["mcp", "-t", "stdio"]
 	expectedArgs := []string{shared.McpServerStdioArg1, shared.McpServerStdioArg2, shared.McpServerStdioArg3}
 	matchingKeys := findMatchingServerKeys(mcpServers, expectedArgs, 2)
+	if len(matchingKeys) == 0 && isUrlServer(mcpServers[serverKey]) {
+		// written by ensureMcpUrlServerInJson
+		matchingKeys = []string{serverKey}
+	}
 
 	// Only remove if exactly one matching server is found
 	if len(matchingKeys) == 0 {
@@ -252,6 +361,17 @@ func findMatchingServerKeys(servers map[string]interface{}, expectedArgs []strin
 	return matchingKeys
 }
 
+// isUrlServer checks if the server config connects to an MCP server by URL rather than running a command
+func isUrlServer(serverConfig interface{}) bool {
+	serverConfigFields, ok := serverConfig.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	_, hasCommand := serverConfigFields["command"]
+	serverURL, hasURL := serverConfigFields["url"].(string)
+	return !hasCommand && hasURL && serverURL != ""
+}
+
 // argsMatch checks if two argument lists are equal
 func argsMatch(ifaceArgs []interface{}, stringArgs []string) bool {
 	if ifaceArgs == nil || stringArgs == nil {
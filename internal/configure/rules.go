@@ -282,3 +282,39 @@ func trimTrailingNewlines(s string) string {
 func trimLeadingNewlines(s string) string {
 	return strings.TrimLeft(s, "\n\r")
 }
+
+// SecurityRules returns the instructions of the SAST rule files that configure installs, without the
+// host-specific front matter. Instructions shared by the always-apply and smart-apply rules are only listed once.
+// This lets the MCP server offer the same guidance as prompts to hosts that cannot install rule files.
+func SecurityRules() string {
+	var merged []string
+	seen := map[string]bool{}
+	for _, rules := range []string{snykRulesAlwaysApply, snykRulesSmartApply} {
+		for _, line := range strings.Split(stripFrontMatter(rules), "\n") {
+			line = strings.TrimRight(line, " \t")
+			if line == "" || seen[line] {
+				continue
+			}
+			seen[line] = true
+			merged = append(merged, line)
+			if strings.HasPrefix(line, "#") {
+				merged = append(merged, "")
+			}
+		}
+	}
+	return strings.Join(merged, "\n")
+}
+
+// stripFrontMatter removes a leading YAML front matter block delimited by "---" lines
+func stripFrontMatter(content string) string {
+	normalized := strings.ReplaceAll(content, "\r\n", "\n")
+	if !strings.HasPrefix(normalized, "---\n") {
+		return strings.TrimSpace(normalized)
+	}
+	rest := normalized[len("---\n"):]
+	end := strings.Index(rest, "\n---\n")
+	if end < 0 {
+		return strings.TrimSpace(normalized)
+	}
+	return strings.TrimSpace(rest[end+len("\n---\n"):])
+}
@@ -0,0 +1,202 @@
+package container
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/snyk/studio-mcp/internal/oss"
+	"github.com/snyk/studio-mcp/internal/types"
+)
+
+const (
+	OriginBaseImage   = "base-image"
+	OriginDockerfile  = "dockerfile"
+	OriginOS          = "os"
+	OriginApplication = "application"
+)
+
+// ConvertContainerJsonToIssues converts the output of `snyk container test --json` into issues and
+// returns the base image with its upgrade recommendations, if known
+func ConvertContainerJsonToIssues(workDir string, res []byte, includeIgnores bool) ([]types.IssueData, *BaseImage, error) {
+	output := strings.TrimSpace(string(res))
+	var scanResults []ScanResult
+	if strings.HasPrefix(output, "[") {
+		if err := json.Unmarshal(res, &scanResults); err != nil {
+			return nil, nil, errors.Join(err, fmt.Errorf("couldn't unmarshal CLI response. Input: %s", output))
+		}
+	} else {
+		var result ScanResult
+		if err := json.Unmarshal(res, &result); err != nil {
+			return nil, nil, errors.Join(err, fmt.Errorf("couldn't unmarshal CLI response. Input: %s", output))
+		}
+		scanResults = append(scanResults, result)
+	}
+
+	issues := []types.IssueData{}
+	var baseImage *BaseImage
+	for _, scanResult := range scanResults {
+		issues = append(issues, convertScanResultToIssues(workDir, &scanResult, includeIgnores)...)
+		if baseImage == nil {
+			baseImage = getBaseImage(&scanResult)
+		}
+	}
+	return issues, baseImage, nil
+}
+
+func convertScanResultToIssues(workDir string, res *ScanResult, includeIgnores bool) []types.IssueData {
+	dockerfile := dockerfilePath(workDir, res)
+	var instructions []dockerfileInstruction
+	if dockerfile != "" {
+		instructions = readDockerfileInstructions(dockerfile)
+	}
+
+	osIssues := oss.ConvertToIssue(workDir, []oss.ScanResult{res.ScanResult}, includeIgnores)
+	issues := make([]types.IssueData, 0, len(osIssues))
+	for _, issue := range osIssues {
+		issue.Origin = osIssueOrigin(issue, dockerfile != "")
+		if dockerfile != "" {
+			issue.FilePath = dockerfile
+			issue.Line = instructionLine(instructions, issue, res.Docker.BaseImage)
+		}
+		issues = append(issues, issue)
+	}
+
+	for _, issue := range oss.ConvertToIssue(workDir, res.Applications, includeIgnores) {
+		issue.Origin = OriginApplication
+		issues = append(issues, issue)
+	}
+	return issues
+}
+
+// osIssueOrigin tells vulnerabilities of the base image from those introduced by Dockerfile instructions.
+// Without a Dockerfile the CLI can't tell them apart.
+func osIssueOrigin(issue types.IssueData, hasDockerfile bool) string {
+	if !hasDockerfile {
+		return OriginOS
+	}
+	if issue.DockerfileInstruction == "" || isFromInstruction(issue.DockerfileInstruction) {
+		return OriginBaseImage
+	}
+	return OriginDockerfile
+}
+
+func getBaseImage(res *ScanResult) *BaseImage {
+	if res.Docker.BaseImage == "" {
+		return nil
+	}
+	baseImage := &BaseImage{Name: res.Docker.BaseImage}
+	for _, advice := range res.Docker.BaseImageRemediation.Advice {
+		if message := strings.TrimSpace(advice.Message); message != "" {
+			baseImage.Recommendations = append(baseImage.Recommendations, message)
+		}
+	}
+	return baseImage
+}
+
+// dockerfilePath returns the absolute path of the Dockerfile given with `--file`, or empty if there is none
+func dockerfilePath(workDir string, res *ScanResult) string {
+	path := res.TargetFile
+	if path == "" {
+		path = res.DisplayTargetFile
+	}
+	if path == "" {
+		return ""
+	}
+	if !filepath.IsAbs(path) && workDir != "" {
+		path = filepath.Join(workDir, path)
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	return absPath
+}
+
+type dockerfileInstruction struct {
+	line        int
+	instruction string
+}
+
+// readDockerfileInstructions reads the instructions of a Dockerfile with the line they start on.
+// Continuation lines are joined and whitespace is normalized, as in the CLI output.
+func readDockerfileInstructions(path string) []dockerfileInstruction {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var instructions []dockerfileInstruction
+	var current []string
+	startLine := 0
+	lineNumber := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if len(current) == 0 && (line == "" || strings.HasPrefix(line, "#")) {
+			continue
+		}
+		if len(current) == 0 {
+			startLine = lineNumber
+		}
+		continued := strings.HasSuffix(line, "\\")
+		current = append(current, strings.TrimSuffix(line, "\\"))
+		if !continued {
+			instructions = append(instructions, dockerfileInstruction{line: startLine, instruction: normalizeInstruction(strings.Join(current, " "))})
+			current = nil
+		}
+	}
+	if len(current) > 0 {
+		instructions = append(instructions, dockerfileInstruction{line: startLine, instruction: normalizeInstruction(strings.Join(current, " "))})
+	}
+	return instructions
+}
+
+// instructionLine returns the Dockerfile line of the instruction that introduced the issue.
+// Base image issues point to the FROM instruction of the base image.
+func instructionLine(instructions []dockerfileInstruction, issue types.IssueData, baseImage string) int {
+	if issue.DockerfileInstruction != "" && !isFromInstruction(issue.DockerfileInstruction) {
+		wanted := normalizeInstruction(issue.DockerfileInstruction)
+		for _, instruction := range instructions {
+			if strings.EqualFold(instruction.instruction, wanted) {
+				return instruction.line
+			}
+		}
+		return 0
+	}
+
+	for _, instruction := range instructions {
+		if !isFromInstruction(instruction.instruction) {
+			continue
+		}
+		if baseImage == "" || fromImage(instruction.instruction) == baseImage {
+			return instruction.line
+		}
+	}
+	return 0
+}
+
+func normalizeInstruction(instruction string) string {
+	return strings.Join(strings.Fields(instruction), " ")
+}
+
+func isFromInstruction(instruction string) bool {
+	fields := strings.Fields(instruction)
+	return len(fields) > 0 && strings.EqualFold(fields[0], "FROM")
+}
+
+// fromImage returns the image of a FROM instruction, skipping flags like --platform
+func fromImage(instruction string) string {
+	for _, field := range strings.Fields(instruction)[1:] {
+		if !strings.HasPrefix(field, "--") {
+			return field
+		}
+	}
+	return ""
+}
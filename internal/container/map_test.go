@@ -0,0 +1,126 @@
+package container
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDockerfile = `# build image
+FROM --platform=linux/amd64 node:18-bullseye AS base
+
+WORKDIR /app
+RUN apt-get update && \
+    apt-get install -y curl
+COPY . .
+`
+
+func writeDockerfile(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Dockerfile"), []byte(testDockerfile), 0644))
+	return dir
+}
+
+func TestConvertContainerJsonToIssues(t *testing.T) {
+	t.Run("with Dockerfile", func(t *testing.T) {
+		workDir := writeDockerfile(t)
+		output := `{
+			"ok": false,
+			"packageManager": "deb",
+			"targetFile": "Dockerfile",
+			"vulnerabilities": [
+				{"id": "SNYK-DEBIAN11-ZLIB-1", "title": "Out-of-bounds Write", "severity": "critical", "packageName": "zlib/zlib1g", "version": "1.2.11", "fixedIn": ["1.2.11.dfsg-2+deb11u2"], "from": ["docker-image|node@18", "zlib/zlib1g@1.2.11"], "packageManager": "deb", "dockerBaseImage": "node:18-bullseye"},
+				{"id": "SNYK-DEBIAN11-CURL-2", "title": "Use After Free", "severity": "high", "packageName": "curl", "version": "7.74.0", "from": ["docker-image|node@18", "curl@7.74.0"], "packageManager": "deb", "dockerfileInstruction": "RUN apt-get update && apt-get install -y curl"}
+			],
+			"docker": {
+				"baseImage": "node:18-bullseye",
+				"baseImageRemediation": {"code": "REMEDIATION_AVAILABLE", "advice": [{"message": "Recommendations for base image upgrade:", "bold": true}, {"message": "node:18.20-bullseye-slim has 12 vulnerabilities"}, {"message": " "}]}
+			},
+			"applications": [
+				{"packageManager": "npm", "displayTargetFile": "/app/package-lock.json", "vulnerabilities": [
+					{"id": "SNYK-JS-LODASH-1", "title": "Prototype Pollution", "severity": "high", "packageName": "lodash", "version": "4.17.15", "from": ["app@1.0.0", "lodash@4.17.15"], "upgradePath": [false, "lodash@4.17.21"], "isUpgradable": true, "packageManager": "npm"}
+				]}
+			]
+		}`
+
+		issues, baseImage, err := ConvertContainerJsonToIssues(workDir, []byte(output), false)
+		require.NoError(t, err)
+
+		require.Len(t, issues, 3)
+		dockerfile := filepath.Join(workDir, "Dockerfile")
+
+		assert.Equal(t, "SNYK-DEBIAN11-ZLIB-1", issues[0].ID)
+		assert.Equal(t, OriginBaseImage, issues[0].Origin)
+		assert.Equal(t, dockerfile, issues[0].FilePath)
+		assert.Equal(t, 2, issues[0].Line)
+		assert.Equal(t, []string{"1.2.11.dfsg-2+deb11u2"}, issues[0].FixedIn)
+
+		assert.Equal(t, "SNYK-DEBIAN11-CURL-2", issues[1].ID)
+		assert.Equal(t, OriginDockerfile, issues[1].Origin)
+		assert.Equal(t, "RUN apt-get update && apt-get install -y curl", issues[1].DockerfileInstruction)
+		assert.Equal(t, 5, issues[1].Line)
+
+		assert.Equal(t, "SNYK-JS-LODASH-1", issues[2].ID)
+		assert.Equal(t, OriginApplication, issues[2].Origin)
+		assert.Equal(t, "/app/package.json", filepath.ToSlash(issues[2].FilePath))
+		assert.Equal(t, "Upgrade to lodash@4.17.21", issues[2].Remediation)
+		assert.Zero(t, issues[2].Line)
+
+		require.NotNil(t, baseImage)
+		assert.Equal(t, "node:18-bullseye", baseImage.Name)
+		assert.Equal(t, []string{"Recommendations for base image upgrade:", "node:18.20-bullseye-slim has 12 vulnerabilities"}, baseImage.Recommendations)
+	})
+
+	t.Run("without Dockerfile", func(t *testing.T) {
+		output := `{"ok": false, "packageManager": "apk", "vulnerabilities": [
+			{"id": "SNYK-ALPINE-OPENSSL-1", "title": "t", "severity": "medium", "packageName": "openssl", "version": "3.0.0", "from": ["docker-image|alpine@3", "openssl@3.0.0"], "packageManager": "apk"},
+			{"id": "SNYK-ALPINE-MUSL-1", "title": "t", "severity": "low", "packageName": "musl", "version": "1.2.0", "from": ["docker-image|alpine@3", "musl@1.2.0"], "packageManager": "apk", "isIgnored": true}
+		]}`
+
+		issues, baseImage, err := ConvertContainerJsonToIssues("", []byte(output), false)
+		require.NoError(t, err)
+
+		require.Len(t, issues, 1)
+		assert.Equal(t, OriginOS, issues[0].Origin)
+		assert.Zero(t, issues[0].Line)
+		assert.Nil(t, baseImage)
+	})
+
+	t.Run("invalid json fails", func(t *testing.T) {
+		_, _, err := ConvertContainerJsonToIssues("", []byte(`{"ok":`), false)
+		assert.Error(t, err)
+	})
+}
+
+func TestReadDockerfileInstructions(t *testing.T) {
+	workDir := writeDockerfile(t)
+
+	instructions := readDockerfileInstructions(filepath.Join(workDir, "Dockerfile"))
+
+	assert.Equal(t, []dockerfileInstruction{
+		{line: 2, instruction: "FROM --platform=linux/amd64 node:18-bullseye AS base"},
+		{line: 4, instruction: "WORKDIR /app"},
+		{line: 5, instruction: "RUN apt-get update && apt-get install -y curl"},
+		{line: 7, instruction: "COPY . ."},
+	}, instructions)
+}
+
+func TestFromImage(t *testing.T) {
+	testCases := []struct {
+		instruction string
+		expected    string
+	}{
+		{instruction: "FROM node:18", expected: "node:18"},
+		{instruction: "FROM --platform=linux/amd64 node:18 AS build", expected: "node:18"},
+		{instruction: "FROM", expected: ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.instruction, func(t *testing.T) {
+			assert.Equal(t, tc.expected, fromImage(tc.instruction))
+		})
+	}
+}
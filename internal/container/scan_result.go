@@ -0,0 +1,32 @@
+package container
+
+import (
+	"github.com/snyk/studio-mcp/internal/oss"
+)
+
+// ScanResult is the result of `snyk container test --json`. The OS package vulnerabilities and
+// the application results share their schema with SCA scan results.
+type ScanResult struct {
+	oss.ScanResult
+	TargetFile   string           `json:"targetFile"`
+	Docker       dockerInfo       `json:"docker"`
+	Applications []oss.ScanResult `json:"applications"`
+}
+
+type dockerInfo struct {
+	BaseImage            string               `json:"baseImage"`
+	BaseImageRemediation baseImageRemediation `json:"baseImageRemediation"`
+}
+
+type baseImageRemediation struct {
+	Code   string `json:"code"`
+	Advice []struct {
+		Message string `json:"message"`
+	} `json:"advice"`
+}
+
+// BaseImage is the base image of the scanned image and the recommended upgrades
+type BaseImage struct {
+	Name            string   `json:"name"`
+	Recommendations []string `json:"recommendations,omitempty"`
+}
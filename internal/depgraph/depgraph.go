@@ -0,0 +1,89 @@
+package depgraph
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// markers of the dependency graphs printed by `snyk test --print-graph`
+const (
+	dataMarker   = "DepGraph data:"
+	targetMarker = "DepGraph target:"
+	endMarker    = "DepGraph end"
+)
+
+// Graph is a dependency graph in the dep-graph JSON format of the CLI
+type Graph struct {
+	PkgManager struct {
+		Name string `json:"name"`
+	} `json:"pkgManager"`
+	Pkgs []struct {
+		ID   string  `json:"id"`
+		Info Package `json:"info"`
+	} `json:"pkgs"`
+	Graph struct {
+		RootNodeID string `json:"rootNodeId"`
+		Nodes      []struct {
+			NodeID string `json:"nodeId"`
+			PkgID  string `json:"pkgId"`
+			Deps   []struct {
+				NodeID string `json:"nodeId"`
+			} `json:"deps"`
+			Info struct {
+				Labels map[string]string `json:"labels"`
+			} `json:"info"`
+		} `json:"nodes"`
+	} `json:"graph"`
+}
+
+// Project is the dependency graph of a scanned project
+type Project struct {
+	TargetFile string
+	Graph      Graph
+}
+
+// ParsePrintGraphOutput parses the dependency graphs of all projects printed by `snyk test --print-graph`
+func ParsePrintGraphOutput(output []byte) ([]Project, error) {
+	var projects []Project
+	var data, target strings.Builder
+	var current *strings.Builder
+
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(output)+1)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch strings.TrimSpace(line) {
+		case dataMarker:
+			data.Reset()
+			target.Reset()
+			current = &data
+		case targetMarker:
+			current = &target
+		case endMarker:
+			if current == nil {
+				continue
+			}
+			project := Project{TargetFile: strings.TrimSpace(target.String())}
+			if err := json.Unmarshal([]byte(data.String()), &project.Graph); err != nil {
+				return nil, errors.Join(err, fmt.Errorf("couldn't unmarshal dependency graph of %q", project.TargetFile))
+			}
+			projects = append(projects, project)
+			current = nil
+		default:
+			if current != nil {
+				current.WriteString(line)
+				current.WriteString("\n")
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(projects) == 0 {
+		return nil, fmt.Errorf("no dependency graph found in CLI output")
+	}
+	return projects, nil
+}
@@ -0,0 +1,120 @@
+package depgraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// app depends on express and jest (dev). lodash is pulled in by express via body-parser and directly by jest.
+// The second body-parser node is pruned and doesn't repeat its dependencies.
+const printGraphOutput = `Some warning printed by the plugin
+DepGraph data:
+{"schemaVersion":"1.3.0","pkgManager":{"name":"npm"},"pkgs":[
+  {"id":"app@1.0.0","info":{"name":"app","version":"1.0.0"}},
+  {"id":"express@4.18.0","info":{"name":"express","version":"4.18.0"}},
+  {"id":"body-parser@1.20.0","info":{"name":"body-parser","version":"1.20.0"}},
+  {"id":"lodash@4.17.15","info":{"name":"lodash","version":"4.17.15"}},
+  {"id":"jest@29.0.0","info":{"name":"jest","version":"29.0.0"}},
+  {"id":"@jest/core@29.0.0","info":{"name":"@jest/core","version":"29.0.0"}}
+],"graph":{"rootNodeId":"root-node","nodes":[
+  {"nodeId":"root-node","pkgId":"app@1.0.0","deps":[{"nodeId":"express@4.18.0"},{"nodeId":"jest@29.0.0"}]},
+  {"nodeId":"express@4.18.0","pkgId":"express@4.18.0","deps":[{"nodeId":"body-parser@1.20.0"}],"info":{"labels":{"scope":"prod"}}},
+  {"nodeId":"body-parser@1.20.0","pkgId":"body-parser@1.20.0","deps":[{"nodeId":"lodash@4.17.15"}]},
+  {"nodeId":"lodash@4.17.15","pkgId":"lodash@4.17.15","deps":[]},
+  {"nodeId":"jest@29.0.0","pkgId":"jest@29.0.0","deps":[{"nodeId":"@jest/core@29.0.0"},{"nodeId":"lodash@4.17.15"}],"info":{"labels":{"scope":"dev"}}},
+  {"nodeId":"@jest/core@29.0.0","pkgId":"@jest/core@29.0.0","deps":[{"nodeId":"body-parser@1.20.0:pruned"}]},
+  {"nodeId":"body-parser@1.20.0:pruned","pkgId":"body-parser@1.20.0","deps":[],"info":{"labels":{"pruned":"true"}}}
+]}}
+DepGraph target:
+package-lock.json
+DepGraph end
+DepGraph data:
+{"pkgManager":{"name":"pip"},"pkgs":[{"id":"web@0.0.0","info":{"name":"web","version":"0.0.0"}}],"graph":{"rootNodeId":"root-node","nodes":[{"nodeId":"root-node","pkgId":"web@0.0.0","deps":[]}]}}
+DepGraph target:
+web/requirements.txt
+DepGraph end
+`
+
+func TestParsePrintGraphOutput(t *testing.T) {
+	projects, err := ParsePrintGraphOutput([]byte(printGraphOutput))
+	require.NoError(t, err)
+
+	require.Len(t, projects, 2)
+	assert.Equal(t, "package-lock.json", projects[0].TargetFile)
+	assert.Equal(t, "npm", projects[0].Graph.PkgManager.Name)
+	assert.Len(t, projects[0].Graph.Graph.Nodes, 7)
+	assert.Equal(t, "web/requirements.txt", projects[1].TargetFile)
+
+	_, err = ParsePrintGraphOutput([]byte("Could not detect supported target files"))
+	assert.ErrorContains(t, err, "no dependency graph found")
+}
+
+func TestInspect(t *testing.T) {
+	projects, err := ParsePrintGraphOutput([]byte(printGraphOutput))
+	require.NoError(t, err)
+
+	t.Run("direct dependencies", func(t *testing.T) {
+		tree := Inspect(projects[0], nil)
+
+		assert.Equal(t, Package{Name: "app", Version: "1.0.0"}, tree.Root)
+		assert.Equal(t, 5, tree.DependencyCount)
+		assert.Equal(t, []Dependency{
+			{Package: Package{Name: "express", Version: "4.18.0"}, Scope: "prod", TransitiveDependencyCount: 2},
+			{Package: Package{Name: "jest", Version: "29.0.0"}, Scope: "dev", TransitiveDependencyCount: 3},
+		}, tree.DirectDependencies)
+		assert.Empty(t, tree.Matches)
+	})
+
+	t.Run("why is a package in the project", func(t *testing.T) {
+		tree := Inspect(projects[0], &Query{PackageName: "lodash"})
+
+		require.Len(t, tree.Matches, 1)
+		match := tree.Matches[0]
+		assert.Equal(t, Package{Name: "lodash", Version: "4.17.15"}, match.Package)
+		assert.False(t, match.Direct)
+		assert.Empty(t, match.Dependencies)
+		assert.Equal(t, []Package{{Name: "express", Version: "4.18.0"}, {Name: "jest", Version: "29.0.0"}}, match.IntroducedBy)
+		assert.Equal(t, [][]string{
+			{"app@1.0.0", "jest@29.0.0", "lodash@4.17.15"},
+			{"app@1.0.0", "express@4.18.0", "body-parser@1.20.0", "lodash@4.17.15"},
+			{"app@1.0.0", "jest@29.0.0", "@jest/core@29.0.0", "body-parser@1.20.0", "lodash@4.17.15"},
+		}, match.Paths, "paths through pruned nodes are resolved")
+		assert.False(t, match.PathsTruncated)
+	})
+
+	t.Run("direct dependency with version", func(t *testing.T) {
+		tree := Inspect(projects[0], &Query{PackageName: "Jest", Version: "29.0.0"})
+
+		require.Len(t, tree.Matches, 1)
+		assert.True(t, tree.Matches[0].Direct)
+		assert.Empty(t, tree.Matches[0].IntroducedBy)
+		assert.Equal(t, []Package{{Name: "@jest/core", Version: "29.0.0"}, {Name: "lodash", Version: "4.17.15"}}, tree.Matches[0].Dependencies)
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		assert.Empty(t, Inspect(projects[0], &Query{PackageName: "lodash", Version: "4.17.21"}).Matches)
+	})
+}
+
+func TestPaths_Truncated(t *testing.T) {
+	// every level of the chain is reached twice, via x and y, which doubles the paths per level
+	idx := index{root: "root", pkgs: map[string]Package{}, deps: map[string][]string{}}
+	previous := "root"
+	for _, level := range []string{"1", "2", "3", "4", "5", "6"} {
+		idx.deps[previous] = []string{"x" + level, "y" + level}
+		idx.deps["x"+level] = []string{level}
+		idx.deps["y"+level] = []string{level}
+		previous = level
+	}
+
+	paths, truncated := idx.paths("6", reachable(map[string][]string{}, "6"))
+	assert.Empty(t, paths, "only packages that reach the target are visited")
+	assert.False(t, truncated)
+
+	all := reachable(idx.deps, "root")
+	paths, truncated = idx.paths("6", all)
+	assert.Len(t, paths, MaxPaths)
+	assert.True(t, truncated)
+}
@@ -0,0 +1,257 @@
+package depgraph
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+const (
+	// MaxPaths is the maximum number of dependency paths returned per matched package
+	MaxPaths = 20
+	// maxPathExpansions bounds the search for paths in large graphs with many shared dependencies
+	maxPathExpansions = 10000
+)
+
+// Package is a package name and version
+type Package struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+func (p Package) String() string {
+	if p.Version == "" {
+		return p.Name
+	}
+	return p.Name + "@" + p.Version
+}
+
+// Dependency is a direct dependency of a project
+type Dependency struct {
+	Package
+	// Scope is the scope reported by the package manager, e.g. `dev` for development dependencies
+	Scope string `json:"scope,omitempty"`
+	// TransitiveDependencyCount is the number of distinct packages the dependency pulls in
+	TransitiveDependencyCount int `json:"transitiveDependencyCount"`
+}
+
+// Match is a package matching a query, with the dependencies that pull it into the project
+type Match struct {
+	Package
+	// Direct is true if the project depends on the package directly
+	Direct bool `json:"direct"`
+	// Dependencies are the direct dependencies of the package
+	Dependencies []Package `json:"dependencies"`
+	// IntroducedBy are all direct dependencies of the project that pull the package in
+	IntroducedBy []Package `json:"introducedBy"`
+	// Paths are dependency chains from the project to the package, shortest first
+	Paths [][]string `json:"paths"`
+	// PathsTruncated is true if there are more paths than returned
+	PathsTruncated bool `json:"pathsTruncated,omitempty"`
+}
+
+// Tree summarizes the dependency graph of a project
+type Tree struct {
+	TargetFile         string       `json:"targetFile,omitempty"`
+	PackageManager     string       `json:"packageManager,omitempty"`
+	Root               Package      `json:"root"`
+	DependencyCount    int          `json:"dependencyCount"`
+	DirectDependencies []Dependency `json:"directDependencies"`
+	// Matches are set if the tree is inspected with a query
+	Matches []Match `json:"matches,omitempty"`
+}
+
+// Query selects the packages to explain by name, and optionally version
+type Query struct {
+	PackageName string
+	Version     string
+}
+
+func (q Query) matches(p Package) bool {
+	return strings.EqualFold(p.Name, q.PackageName) && (q.Version == "" || p.Version == q.Version)
+}
+
+// index is a dependency graph on package level. Nodes of the same package are merged, so that pruned
+// nodes, which don't repeat their dependencies, are resolved.
+type index struct {
+	root   string
+	pkgs   map[string]Package
+	deps   map[string][]string
+	scopes map[string]string
+}
+
+func newIndex(g Graph) index {
+	idx := index{
+		pkgs:   make(map[string]Package, len(g.Pkgs)),
+		deps:   make(map[string][]string),
+		scopes: make(map[string]string),
+	}
+	for _, pkg := range g.Pkgs {
+		idx.pkgs[pkg.ID] = pkg.Info
+	}
+	nodePkgs := make(map[string]string, len(g.Graph.Nodes))
+	for _, node := range g.Graph.Nodes {
+		nodePkgs[node.NodeID] = node.PkgID
+		if _, ok := idx.pkgs[node.PkgID]; !ok {
+			idx.pkgs[node.PkgID] = parsePkgID(node.PkgID)
+		}
+		if scope := node.Info.Labels["scope"]; scope != "" && idx.scopes[node.PkgID] == "" {
+			idx.scopes[node.PkgID] = scope
+		}
+	}
+	idx.root = nodePkgs[g.Graph.RootNodeID]
+	for _, node := range g.Graph.Nodes {
+		for _, dep := range node.Deps {
+			depPkg, ok := nodePkgs[dep.NodeID]
+			if ok && !slices.Contains(idx.deps[node.PkgID], depPkg) {
+				idx.deps[node.PkgID] = append(idx.deps[node.PkgID], depPkg)
+			}
+		}
+	}
+	return idx
+}
+
+// parsePkgID splits a `name@version` package ID, keeping the @ of scoped npm packages in the name
+func parsePkgID(id string) Package {
+	if at := strings.LastIndex(id, "@"); at > 0 {
+		return Package{Name: id[:at], Version: id[at+1:]}
+	}
+	return Package{Name: id}
+}
+
+// reachable returns the packages reachable from the given packages along the edges, including the given packages
+func reachable(edges map[string][]string, from ...string) map[string]bool {
+	visited := make(map[string]bool)
+	stack := slices.Clone(from)
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+		stack = append(stack, edges[current]...)
+	}
+	return visited
+}
+
+func (idx index) packages(ids []string) []Package {
+	packages := make([]Package, 0, len(ids))
+	for _, id := range ids {
+		packages = append(packages, idx.pkgs[id])
+	}
+	slices.SortFunc(packages, comparePackages)
+	return packages
+}
+
+// paths returns the shortest dependency chains from the root to the target, only visiting packages that reach it
+func (idx index) paths(target string, reachesTarget map[string]bool) ([][]string, bool) {
+	var paths [][]string
+	queue := [][]string{{idx.root}}
+	for expansions := 0; len(queue) > 0; expansions++ {
+		if expansions == maxPathExpansions {
+			return paths, true
+		}
+		path := queue[0]
+		queue = queue[1:]
+		for _, dep := range idx.deps[path[len(path)-1]] {
+			if !reachesTarget[dep] || slices.Contains(path, dep) {
+				continue
+			}
+			next := append(slices.Clone(path), dep)
+			if dep != target {
+				queue = append(queue, next)
+				continue
+			}
+			if len(paths) == MaxPaths {
+				return paths, true
+			}
+			paths = append(paths, next)
+		}
+	}
+	return paths, false
+}
+
+func (idx index) pathStrings(paths [][]string) [][]string {
+	result := make([][]string, 0, len(paths))
+	for _, path := range paths {
+		chain := make([]string, 0, len(path))
+		for _, id := range path {
+			chain = append(chain, idx.pkgs[id].String())
+		}
+		result = append(result, chain)
+	}
+	return result
+}
+
+// Inspect summarizes the direct dependencies of the project. With a query, it also explains why the matching
+// packages are in the project.
+func Inspect(project Project, query *Query) Tree {
+	idx := newIndex(project.Graph)
+	tree := Tree{
+		TargetFile:         project.TargetFile,
+		PackageManager:     project.Graph.PkgManager.Name,
+		Root:               idx.pkgs[idx.root],
+		DirectDependencies: []Dependency{},
+	}
+	all := reachable(idx.deps, idx.root)
+	tree.DependencyCount = len(all) - 1
+
+	for _, dep := range idx.deps[idx.root] {
+		tree.DirectDependencies = append(tree.DirectDependencies, Dependency{
+			Package:                   idx.pkgs[dep],
+			Scope:                     idx.scopes[dep],
+			TransitiveDependencyCount: len(reachable(idx.deps, dep)) - 1,
+		})
+	}
+	slices.SortFunc(tree.DirectDependencies, func(a, b Dependency) int {
+		return comparePackages(a.Package, b.Package)
+	})
+
+	if query == nil {
+		return tree
+	}
+
+	var targets []string
+	for id := range all {
+		if id != idx.root && query.matches(idx.pkgs[id]) {
+			targets = append(targets, id)
+		}
+	}
+	if len(targets) == 0 {
+		return tree
+	}
+	slices.SortFunc(targets, func(a, b string) int {
+		return comparePackages(idx.pkgs[a], idx.pkgs[b])
+	})
+
+	dependents := make(map[string][]string)
+	for id, deps := range idx.deps {
+		for _, dep := range deps {
+			dependents[dep] = append(dependents[dep], id)
+		}
+	}
+	for _, target := range targets {
+		reachesTarget := reachable(dependents, target)
+		var introducedBy []string
+		for _, dep := range idx.deps[idx.root] {
+			if reachesTarget[dep] && dep != target {
+				introducedBy = append(introducedBy, dep)
+			}
+		}
+		paths, truncated := idx.paths(target, reachesTarget)
+		tree.Matches = append(tree.Matches, Match{
+			Package:        idx.pkgs[target],
+			Direct:         slices.Contains(idx.deps[idx.root], target),
+			Dependencies:   idx.packages(idx.deps[target]),
+			IntroducedBy:   idx.packages(introducedBy),
+			Paths:          idx.pathStrings(paths),
+			PathsTruncated: truncated,
+		})
+	}
+	return tree
+}
+
+func comparePackages(a, b Package) int {
+	return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.Version, b.Version))
+}
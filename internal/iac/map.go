@@ -0,0 +1,102 @@
+package iac
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/snyk/studio-mcp/internal/types"
+)
+
+// ConvertIacJsonToIssues converts the output of `snyk iac test --json`, a single result or an array of
+// results for multiple files, into issues. It also returns the files that the CLI failed to parse or test.
+func ConvertIacJsonToIssues(workDir string, res []byte, includeIgnores bool) ([]types.IssueData, []FileError, error) {
+	output := strings.TrimSpace(string(res))
+	var scanResults []ScanResult
+	if strings.HasPrefix(output, "[") {
+		if err := json.Unmarshal(res, &scanResults); err != nil {
+			return nil, nil, errors.Join(err, fmt.Errorf("couldn't unmarshal CLI response. Input: %s", output))
+		}
+	} else {
+		var result ScanResult
+		if err := json.Unmarshal(res, &result); err != nil {
+			return nil, nil, errors.Join(err, fmt.Errorf("couldn't unmarshal CLI response. Input: %s", output))
+		}
+		scanResults = append(scanResults, result)
+	}
+
+	issues := []types.IssueData{}
+	var fileErrors []FileError
+	duplicateCheckMap := map[string]bool{}
+	for _, scanResult := range scanResults {
+		targetFilePath := getAbsTargetFilePath(workDir, scanResult)
+		if scanResult.Error != "" {
+			fileErrors = append(fileErrors, FileError{Path: firstNonEmpty(targetFilePath, scanResult.Path), Error: scanResult.Error})
+			continue
+		}
+		for _, issue := range scanResult.InfrastructureAsCodeIssues {
+			if !includeIgnores && issue.IsIgnored {
+				continue
+			}
+			snykIssue := toIssue(issue, targetFilePath)
+			duplicateKey := strings.Join([]string{targetFilePath, snykIssue.ID, snykIssue.ResourcePath, strconv.Itoa(snykIssue.Line)}, "|")
+			if duplicateCheckMap[duplicateKey] {
+				continue
+			}
+			duplicateCheckMap[duplicateKey] = true
+			issues = append(issues, snykIssue)
+		}
+	}
+	return issues, fileErrors, nil
+}
+
+func toIssue(issue iacIssue, targetFilePath string) types.IssueData {
+	id := issue.PublicId
+	if id == "" {
+		id = issue.Id
+	}
+
+	return types.IssueData{
+		ID:           id,
+		Title:        issue.Title,
+		Severity:     issue.Severity,
+		FilePath:     targetFilePath,
+		Line:         issue.LineNumber,
+		Message:      firstNonEmpty(issue.IacDescription.Issue, issue.Issue),
+		Impact:       firstNonEmpty(issue.IacDescription.Impact, issue.Impact),
+		Remediation:  firstNonEmpty(issue.IacDescription.Resolve, issue.Resolve),
+		ResourcePath: resourcePath(issue),
+		IsIgnored:    issue.IsIgnored,
+	}
+}
+
+// resourcePath returns the path to the misconfigured attribute, e.g. `resource.aws_s3_bucket[logs].acl`
+func resourcePath(issue iacIssue) string {
+	if issue.Msg != "" {
+		return issue.Msg
+	}
+	return strings.Join(issue.Path, ".")
+}
+
+func getAbsTargetFilePath(workDir string, scanResult ScanResult) string {
+	if filepath.IsAbs(scanResult.TargetFilePath) {
+		return scanResult.TargetFilePath
+	}
+	targetFile := scanResult.TargetFile
+	if targetFile == "" || filepath.IsAbs(targetFile) {
+		return targetFile
+	}
+	return filepath.Join(workDir, targetFile)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
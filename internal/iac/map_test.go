@@ -0,0 +1,136 @@
+package iac
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/studio-mcp/internal/types"
+)
+
+const s3BucketIssue = `{
+	"id": "SNYK-CC-TF-1",
+	"publicId": "SNYK-CC-TF-1",
+	"title": "S3 bucket is publicly readable",
+	"severity": "high",
+	"issue": "S3 bucket is publicly readable",
+	"impact": "Anyone can read the bucket content",
+	"resolve": "Set acl to private",
+	"msg": "resource.aws_s3_bucket[logs].acl",
+	"path": ["resource", "aws_s3_bucket[logs]", "acl"],
+	"lineNumber": 3,
+	"iacDescription": {
+		"issue": "The ACL allows public read access",
+		"impact": "Anyone can list and read objects in the bucket",
+		"resolve": "Set ` + "`aws_s3_bucket.acl`" + ` to ` + "`private`" + `"
+	},
+	"type": "terraformconfig"
+}`
+
+func TestConvertIacJsonToIssues(t *testing.T) {
+	workDir := filepath.Join("/", "repo")
+	expectedS3Issue := types.IssueData{
+		ID:           "SNYK-CC-TF-1",
+		Title:        "S3 bucket is publicly readable",
+		Severity:     "high",
+		FilePath:     filepath.Join(workDir, "infra", "main.tf"),
+		Line:         3,
+		Message:      "The ACL allows public read access",
+		Impact:       "Anyone can list and read objects in the bucket",
+		Remediation:  "Set `aws_s3_bucket.acl` to `private`",
+		ResourcePath: "resource.aws_s3_bucket[logs].acl",
+	}
+
+	testCases := []struct {
+		name           string
+		output         string
+		includeIgnores bool
+		expected       []types.IssueData
+		expectedErrors []FileError
+	}{
+		{
+			name:     "single file result",
+			output:   `{"ok": false, "targetFile": "infra/main.tf", "infrastructureAsCodeIssues": [` + s3BucketIssue + `]}`,
+			expected: []types.IssueData{expectedS3Issue},
+		},
+		{
+			name: "multiple file results",
+			output: `[
+				{"ok": false, "targetFile": "infra/main.tf", "infrastructureAsCodeIssues": [` + s3BucketIssue + `]},
+				{"ok": true, "targetFile": "k8s/deployment.yaml", "infrastructureAsCodeIssues": []}
+			]`,
+			expected: []types.IssueData{expectedS3Issue},
+		},
+		{
+			name:     "duplicate issues are reported once",
+			output:   `{"ok": false, "targetFile": "infra/main.tf", "infrastructureAsCodeIssues": [` + s3BucketIssue + `,` + s3BucketIssue + `]}`,
+			expected: []types.IssueData{expectedS3Issue},
+		},
+		{
+			name: "falls back to top-level description and path",
+			output: `{"ok": false, "targetFile": "k8s/deployment.yaml", "infrastructureAsCodeIssues": [{
+				"id": "SNYK-CC-K8S-1", "title": "Container is running in privileged mode", "severity": "medium",
+				"issue": "Container is running in privileged mode", "impact": "Compromised container could compromise the host",
+				"resolve": "Set privileged to false", "path": ["spec", "containers[web]", "securityContext", "privileged"], "lineNumber": 12
+			}]}`,
+			expected: []types.IssueData{{
+				ID:           "SNYK-CC-K8S-1",
+				Title:        "Container is running in privileged mode",
+				Severity:     "medium",
+				FilePath:     filepath.Join(workDir, "k8s", "deployment.yaml"),
+				Line:         12,
+				Message:      "Container is running in privileged mode",
+				Impact:       "Compromised container could compromise the host",
+				Remediation:  "Set privileged to false",
+				ResourcePath: "spec.containers[web].securityContext.privileged",
+			}},
+		},
+		{
+			name:     "ignored issues are skipped",
+			output:   `{"ok": false, "targetFile": "main.tf", "infrastructureAsCodeIssues": [{"id": "SNYK-CC-TF-2", "isIgnored": true}]}`,
+			expected: []types.IssueData{},
+		},
+		{
+			name:           "ignored issues are included on request",
+			output:         `{"ok": false, "targetFile": "main.tf", "infrastructureAsCodeIssues": [{"id": "SNYK-CC-TF-2", "isIgnored": true}]}`,
+			includeIgnores: true,
+			expected:       []types.IssueData{{ID: "SNYK-CC-TF-2", FilePath: filepath.Join(workDir, "main.tf"), IsIgnored: true}},
+		},
+		{
+			name:     "absolute target file path is kept",
+			output:   `{"ok": false, "targetFile": "main.tf", "targetFilePath": "/other/main.tf", "infrastructureAsCodeIssues": [{"id": "SNYK-CC-TF-3"}]}`,
+			expected: []types.IssueData{{ID: "SNYK-CC-TF-3", FilePath: "/other/main.tf"}},
+		},
+		{
+			name:           "error result is reported",
+			output:         `{"ok": false, "error": "Could not find any valid IaC files", "path": "/repo"}`,
+			expected:       []types.IssueData{},
+			expectedErrors: []FileError{{Path: "/repo", Error: "Could not find any valid IaC files"}},
+		},
+		{
+			name: "files that failed are reported along with the issues of other files",
+			output: `[
+				{"ok": false, "targetFile": "infra/main.tf", "infrastructureAsCodeIssues": [` + s3BucketIssue + `]},
+				{"ok": false, "error": "Failed to parse Terraform file", "path": "/repo/infra/broken.tf", "targetFile": "infra/broken.tf"}
+			]`,
+			expected:       []types.IssueData{expectedS3Issue},
+			expectedErrors: []FileError{{Path: filepath.Join(workDir, "infra", "broken.tf"), Error: "Failed to parse Terraform file"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			issues, fileErrors, err := ConvertIacJsonToIssues(workDir, []byte(tc.output), tc.includeIgnores)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, issues)
+			assert.Equal(t, tc.expectedErrors, fileErrors)
+		})
+	}
+
+	t.Run("invalid json fails", func(t *testing.T) {
+		_, _, err := ConvertIacJsonToIssues(workDir, []byte(`{"ok": `), false)
+		assert.Error(t, err)
+	})
+}
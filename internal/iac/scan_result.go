@@ -0,0 +1,41 @@
+package iac
+
+// ScanResult is the result of `snyk iac test --json` for a single file
+type ScanResult struct {
+	Ok                         bool       `json:"ok"`
+	Error                      string     `json:"error,omitempty"`
+	Path                       string     `json:"path"`
+	TargetFile                 string     `json:"targetFile"`
+	TargetFilePath             string     `json:"targetFilePath"`
+	ProjectType                string     `json:"projectType"`
+	InfrastructureAsCodeIssues []iacIssue `json:"infrastructureAsCodeIssues"`
+}
+
+// FileError is a file that the CLI failed to parse or test
+type FileError struct {
+	Path  string `json:"path"`
+	Error string `json:"error"`
+}
+
+type iacIssue struct {
+	Id             string         `json:"id"`
+	PublicId       string         `json:"publicId"`
+	Title          string         `json:"title"`
+	Severity       string         `json:"severity"`
+	Issue          string         `json:"issue"`
+	Impact         string         `json:"impact"`
+	Resolve        string         `json:"resolve"`
+	Msg            string         `json:"msg"`
+	Path           []string       `json:"path"`
+	LineNumber     int            `json:"lineNumber"`
+	Documentation  string         `json:"documentation,omitempty"`
+	IsIgnored      bool           `json:"isIgnored,omitempty"`
+	IacDescription iacDescription `json:"iacDescription"`
+	Type           string         `json:"type"`
+}
+
+type iacDescription struct {
+	Issue   string `json:"issue"`
+	Impact  string `json:"impact"`
+	Resolve string `json:"resolve"`
+}
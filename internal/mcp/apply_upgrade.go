@@ -0,0 +1,137 @@
+/*
+ * © 2025 Snyk Limited
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/snyk/go-application-framework/pkg/configuration"
+	"github.com/snyk/go-application-framework/pkg/workflow"
+
+	breakabilityapi "github.com/snyk/studio-mcp/internal/apiclients/breakability/2025-11-05"
+	"github.com/snyk/studio-mcp/internal/authentication"
+	"github.com/snyk/studio-mcp/internal/breakability"
+	"github.com/snyk/studio-mcp/internal/upgrade"
+)
+
+const breakingUpgradeMsg = "The upgrade is likely breaking and was not applied. Inform the user of the breaking change, and only if they confirm the upgrade, call the tool again with `check_breakability` set to false."
+
+const noAssessmentMsg = "No breakability assessment is available, the upgrade was not applied. Inform the user, and only if they confirm the upgrade, call the tool again with `check_breakability` set to false."
+
+// ApplyUpgradeResult is the response of snyk_apply_upgrade
+type ApplyUpgradeResult struct {
+	*upgrade.Upgrade
+	Applied      bool                               `json:"applied"`
+	Breakability *breakability.BreakabilityResponse `json:"breakability,omitempty"`
+	Message      string                             `json:"message,omitempty"`
+}
+
+// snykApplyUpgradeHandler upgrades a direct dependency in its manifest. With check_breakability, the upgrade is
+// assessed first and not applied if it is likely breaking.
+func (m *McpLLMBinding) snykApplyUpgradeHandler(invocationCtx workflow.InvocationContext, toolDef SnykMcpToolsDefinition) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		logger := m.logger.With().Str("method", toolDef.Name).Logger()
+		logger.Debug().Str("toolName", toolDef.Name).Msg("Received call for tool")
+
+		args := request.GetArguments()
+		path, err := getRequiredStringArg(args, "path")
+		if err != nil {
+			return nil, err
+		}
+		packageName, err := getRequiredStringArg(args, "package_name")
+		if err != nil {
+			return nil, err
+		}
+		version, err := getRequiredStringArg(args, "version")
+		if err != nil {
+			return nil, err
+		}
+		checkBreakability := request.GetBool("check_breakability", false)
+		dryRun := request.GetBool("dry_run", false)
+
+		if trustErr := m.folderTrustError(invocationCtx, toolDef, path); trustErr != "" {
+			logger.Error().Msg(trustErr)
+			return mcp.NewToolResultText(trustErr), nil
+		}
+
+		plan, err := upgrade.Plan(path, packageName, strings.TrimSpace(version))
+		if err != nil {
+			return mcp.NewToolResultText(fmt.Sprintf("Error: %s", err.Error())), nil
+		}
+		result := ApplyUpgradeResult{Upgrade: plan}
+
+		if checkBreakability {
+			clientInfo := ClientInfoFromContext(ctx)
+			m.updateGafConfigWithIntegrationEnvironment(invocationCtx, clientInfo.Name, clientInfo.Version)
+
+			user, whoAmiErr := authentication.CallWhoAmI(&logger, invocationCtx.GetEngine())
+			if whoAmiErr != nil || user == nil {
+				return mcp.NewToolResultText("User not authenticated. Please run 'snyk_auth' first"), nil
+			}
+			orgId, orgErr := configuredOrgId(invocationCtx.GetEngine().GetConfiguration())
+			if orgErr != "" {
+				return mcp.NewToolResultText(orgErr), nil
+			}
+
+			endpoint, err := url.JoinPath(invocationCtx.GetEngine().GetConfiguration().GetString(configuration.API_URL), "hidden")
+			if err != nil {
+				return nil, err
+			}
+
+			result.Breakability, err = m.assessBreakability(ctx, invocationCtx, &logger, endpoint, orgId, breakability.PackageUpgrade{
+				Name:        packageName,
+				FromVersion: plan.FromVersion,
+				ToVersion:   plan.ToVersion,
+			})
+			if errors.Is(err, errNoAssessment) {
+				result.Message = noAssessmentMsg
+				return marshalApplyUpgradeResult(result)
+			}
+			if err != nil {
+				return mcp.NewToolResultText(fmt.Sprintf("Error: failed to assess the breakability of the upgrade: %s", err.Error())), nil
+			}
+			if result.Breakability != nil && result.Breakability.RiskLevel == string(breakabilityapi.High) {
+				result.Message = breakingUpgradeMsg
+				return marshalApplyUpgradeResult(result)
+			}
+		}
+
+		if !dryRun {
+			if err = plan.Apply(); err != nil {
+				return mcp.NewToolResultText(fmt.Sprintf("Error: failed to write %s: %s", plan.ManifestFile, err.Error())), nil
+			}
+			result.Applied = true
+			logger.Info().Str("package", packageName).Str("from", plan.FromVersion).Str("to", plan.ToVersion).Str("manifest", plan.ManifestFile).Msg("Applied upgrade")
+		}
+		return marshalApplyUpgradeResult(result)
+	}
+}
+
+func marshalApplyUpgradeResult(result ApplyUpgradeResult) (*mcp.CallToolResult, error) {
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
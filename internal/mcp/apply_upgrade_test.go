@@ -0,0 +1,164 @@
+/*
+ * © 2025 Snyk Limited
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const upgradeManifest = `{
+  "name": "app",
+  "dependencies": {
+    "express": "^4.18.0"
+  }
+}
+`
+
+func TestSnykApplyUpgradeHandler(t *testing.T) {
+	fixture := setupTestFixture(t)
+	tool := getToolWithName(t, fixture.tools, ToolName.ApplyUpgrade)
+	require.NotNil(t, tool)
+	handler := fixture.binding.snykApplyUpgradeHandler(fixture.invocationContext, *tool)
+	writeProject := func(t *testing.T) (string, string) {
+		t.Helper()
+		projectDir := t.TempDir()
+		manifest := filepath.Join(projectDir, "package.json")
+		require.NoError(t, os.WriteFile(manifest, []byte(upgradeManifest), 0600))
+		return projectDir, manifest
+	}
+	readManifest := func(t *testing.T, manifest string) string {
+		t.Helper()
+		content, err := os.ReadFile(manifest)
+		require.NoError(t, err)
+		return string(content)
+	}
+
+	t.Run("applies the upgrade", func(t *testing.T) {
+		projectDir, manifest := writeProject(t)
+
+		var result ApplyUpgradeResult
+		require.NoError(t, json.Unmarshal([]byte(callToolWithArgs(t, handler, map[string]any{"path": projectDir, "package_name": "express", "version": "4.21.2"})), &result))
+
+		assert.True(t, result.Applied)
+		assert.Equal(t, manifest, result.ManifestFile)
+		assert.Equal(t, "4.18.0", result.FromVersion)
+		assert.Contains(t, result.Diff, "-    \"express\": \"^4.18.0\"\n+    \"express\": \"^4.21.2\"\n")
+		assert.Contains(t, readManifest(t, manifest), `"express": "^4.21.2"`)
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		projectDir, manifest := writeProject(t)
+
+		var result ApplyUpgradeResult
+		require.NoError(t, json.Unmarshal([]byte(callToolWithArgs(t, handler, map[string]any{"path": projectDir, "package_name": "express", "version": "4.21.2", "dry_run": true})), &result))
+
+		assert.False(t, result.Applied)
+		assert.NotEmpty(t, result.Diff)
+		assert.Equal(t, upgradeManifest, readManifest(t, manifest))
+	})
+
+	t.Run("not a direct dependency", func(t *testing.T) {
+		projectDir, _ := writeProject(t)
+
+		output := callToolWithArgs(t, handler, map[string]any{"path": projectDir, "package_name": "qs", "version": "6.11.0"})
+
+		assert.Contains(t, output, "Error: qs is not a direct dependency")
+	})
+}
+
+func TestSnykApplyUpgradeHandler_Breakability(t *testing.T) {
+	const orgID = "44444444-4444-4444-4444-444444444444"
+
+	testCases := []struct {
+		name            string
+		riskLevel       string
+		expectedApplied bool
+	}{
+		{name: "breaking upgrade is not applied", riskLevel: "high", expectedApplied: false},
+		{name: "non-breaking upgrade is applied", riskLevel: "low", expectedApplied: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fixture := setupTestFixture(t)
+			tool := getToolWithName(t, fixture.tools, ToolName.ApplyUpgrade)
+			require.NotNil(t, tool)
+			capturedBody := map[string]interface{}{}
+			respBody := map[string]interface{}{
+				"jsonapi": map[string]interface{}{"version": "1.0"},
+				"data": []interface{}{
+					map[string]interface{}{
+						"id":   "55555555-5555-5555-5555-555555555555",
+						"type": "breakability",
+						"attributes": map[string]interface{}{
+							"package_upgrade": map[string]interface{}{"name": "express", "from_version": "4.18.0", "to_version": "5.0.0"},
+							"risk_level":      tc.riskLevel,
+							"summary":         "assessment",
+						},
+					},
+				},
+			}
+			configureBreakabilityFixture(t, fixture, startBreakabilityMockServer(t, orgID, http.StatusOK, respBody, &capturedBody), orgID)
+			handler := fixture.binding.snykApplyUpgradeHandler(fixture.invocationContext, *tool)
+			projectDir := t.TempDir()
+			manifest := filepath.Join(projectDir, "package.json")
+			require.NoError(t, os.WriteFile(manifest, []byte(upgradeManifest), 0600))
+
+			var result ApplyUpgradeResult
+			output := callToolWithArgs(t, handler, map[string]any{"path": manifest, "package_name": "express", "version": "5.0.0", "check_breakability": true})
+			require.NoError(t, json.Unmarshal([]byte(output), &result))
+
+			assert.Equal(t, tc.expectedApplied, result.Applied)
+			require.NotNil(t, result.Breakability)
+			assert.Equal(t, tc.riskLevel, result.Breakability.RiskLevel)
+			content, err := os.ReadFile(manifest)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedApplied, strings.Contains(string(content), `"express": "^5.0.0"`))
+			if !tc.expectedApplied {
+				assert.Equal(t, breakingUpgradeMsg, result.Message)
+			}
+		})
+	}
+
+	t.Run("upgrade is not applied without an assessment", func(t *testing.T) {
+		fixture := setupTestFixture(t)
+		tool := getToolWithName(t, fixture.tools, ToolName.ApplyUpgrade)
+		require.NotNil(t, tool)
+		configureBreakabilityFixture(t, fixture, startBreakabilityMockServer(t, orgID, http.StatusInternalServerError, nil, nil), orgID)
+		handler := fixture.binding.snykApplyUpgradeHandler(fixture.invocationContext, *tool)
+		manifest := filepath.Join(t.TempDir(), "package.json")
+		require.NoError(t, os.WriteFile(manifest, []byte(upgradeManifest), 0600))
+
+		var result ApplyUpgradeResult
+		output := callToolWithArgs(t, handler, map[string]any{"path": manifest, "package_name": "express", "version": "5.0.0", "check_breakability": true})
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+
+		assert.False(t, result.Applied)
+		assert.Nil(t, result.Breakability)
+		assert.Equal(t, noAssessmentMsg, result.Message)
+		content, err := os.ReadFile(manifest)
+		require.NoError(t, err)
+		assert.Equal(t, upgradeManifest, string(content))
+	})
+}
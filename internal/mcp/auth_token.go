@@ -0,0 +1,92 @@
+/*
+ * © 2025 Snyk Limited
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/snyk/go-application-framework/pkg/configuration"
+	"github.com/snyk/studio-mcp/shared"
+)
+
+const (
+	bearerPrefix = "Bearer "
+	// authTokenFile is where a generated token is written to, relative to the user's config directory
+	authTokenFile = "snyk/snyk-mcp-auth-token"
+)
+
+// resolveAuthToken determines the bearer token HTTP clients must present.
+// Priority: explicit flag > environment variable > generated (if requested) > none.
+// An empty token disables bearer authentication.
+func resolveAuthToken(config configuration.Configuration) (token string, generated bool, err error) {
+	if token = strings.TrimSpace(config.GetString(shared.AuthTokenParam)); token != "" {
+		return token, false, nil
+	}
+	if token = strings.TrimSpace(os.Getenv(shared.AuthTokenEnvVar)); token != "" {
+		return token, false, nil
+	}
+	if config.GetBool(shared.GenerateAuthTokenParam) {
+		token, err = generateAuthToken()
+		return token, err == nil, err
+	}
+	return "", false, nil
+}
+
+func generateAuthToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate auth token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// isAuthorizedRequest checks the request's bearer token against the expected token in constant time.
+// If no token is expected, every request is authorized.
+func isAuthorizedRequest(r *http.Request, expectedToken string) bool {
+	if expectedToken == "" {
+		return true
+	}
+	authHeader := r.Header.Get("Authorization")
+	if len(authHeader) < len(bearerPrefix) || !strings.EqualFold(authHeader[:len(bearerPrefix)], bearerPrefix) {
+		return false
+	}
+	receivedToken := strings.TrimSpace(authHeader[len(bearerPrefix):])
+	return subtle.ConstantTimeCompare([]byte(receivedToken), []byte(expectedToken)) == 1
+}
+
+// writeAuthTokenFile writes a generated token to a file only the current user can read.
+// The token is not printed, as the stderr output of MCP servers usually ends up in host logs.
+func writeAuthTokenFile(path string, token string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create auth token directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(token+"\n"), 0600); err != nil {
+		return fmt.Errorf("failed to write auth token file: %w", err)
+	}
+	// WriteFile keeps the permissions of an existing file
+	if err := os.Chmod(path, 0600); err != nil {
+		return fmt.Errorf("failed to restrict permissions of auth token file: %w", err)
+	}
+	return nil
+}
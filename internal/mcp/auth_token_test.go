@@ -0,0 +1,112 @@
+/*
+ * © 2025 Snyk Limited
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package mcp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/snyk/go-application-framework/pkg/configuration"
+	"github.com/snyk/studio-mcp/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveAuthToken(t *testing.T) {
+	tests := []struct {
+		name          string
+		flagValue     string
+		envValue      string
+		generate      bool
+		expected      string
+		wantGenerated bool
+	}{
+		{name: "no token configured disables auth", expected: ""},
+		{name: "flag takes precedence over env", flagValue: "from-flag", envValue: "from-env", expected: "from-flag"},
+		{name: "env is used when flag is empty", envValue: "from-env", expected: "from-env"},
+		{name: "explicit token wins over generation", envValue: "from-env", generate: true, expected: "from-env"},
+		{name: "generates token when requested", generate: true, wantGenerated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(shared.AuthTokenEnvVar, tt.envValue)
+			config := configuration.NewWithOpts()
+			config.Set(shared.AuthTokenParam, tt.flagValue)
+			config.Set(shared.GenerateAuthTokenParam, tt.generate)
+
+			token, generated, err := resolveAuthToken(config)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantGenerated, generated)
+			if tt.wantGenerated {
+				assert.Len(t, token, 64)
+				return
+			}
+			assert.Equal(t, tt.expected, token)
+		})
+	}
+}
+
+func TestIsAuthorizedRequest(t *testing.T) {
+	tests := []struct {
+		name          string
+		expectedToken string
+		authorization string
+		expected      bool
+	}{
+		{name: "no expected token authorizes everything", expectedToken: "", authorization: "", expected: true},
+		{name: "matching bearer token", expectedToken: "secret", authorization: "Bearer secret", expected: true},
+		{name: "bearer scheme is case insensitive", expectedToken: "secret", authorization: "bearer secret", expected: true},
+		{name: "missing header", expectedToken: "secret", authorization: "", expected: false},
+		{name: "wrong token", expectedToken: "secret", authorization: "Bearer other", expected: false},
+		{name: "token prefix only", expectedToken: "secret", authorization: "Bearer secr", expected: false},
+		{name: "wrong scheme", expectedToken: "secret", authorization: "Basic secret", expected: false},
+		{name: "bare token without scheme", expectedToken: "secret", authorization: "secret", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+
+			assert.Equal(t, tt.expected, isAuthorizedRequest(req, tt.expectedToken))
+		})
+	}
+}
+
+func TestWriteAuthTokenFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snyk", "snyk-mcp-auth-token")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte("old-token\n"), 0644))
+
+	require.NoError(t, writeAuthTokenFile(path, "new-token"))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "new-token\n", string(content))
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	}
+}
@@ -0,0 +1,312 @@
+/*
+ * © 2025 Snyk Limited
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/rs/zerolog"
+	"github.com/snyk/go-application-framework/pkg/workflow"
+	"github.com/snyk/studio-mcp/internal/types"
+)
+
+// DefaultBaselineRef is the git ref that scans are compared against if the baseline parameter is empty
+const DefaultBaselineRef = "HEAD"
+
+// BaselineComparison summarizes how a scan result differs from the scan of a git baseline
+type BaselineComparison struct {
+	Ref                   string `json:"ref"`
+	Commit                string `json:"commit"`
+	FixedIssueCount       int    `json:"fixedIssueCount"`
+	PreExistingIssueCount int    `json:"preExistingIssueCount"`
+}
+
+// baselineScan is the scan result of the baseline commit
+type baselineScan struct {
+	ref    string
+	commit string
+	issues []types.IssueData
+}
+
+// baselineCheckout is the content of the scanned path at the baseline commit, exported to a temp directory
+type baselineCheckout struct {
+	dir    string
+	commit string
+	// path is the scanned path, relocated into dir
+	path string
+	// empty is true if the scanned path did not exist at the baseline commit
+	empty bool
+}
+
+func (c *baselineCheckout) cleanup() {
+	_ = os.RemoveAll(c.dir)
+}
+
+// checkoutBaseline exports the files below path as of the given ref of the enclosing git repository
+func checkoutBaseline(path string, ref string) (*baselineCheckout, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := git.PlainOpenWithOptions(absPath, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, fmt.Errorf("cannot compare against baseline, %s is not in a git repository: %w", path, err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("cannot compare against baseline, repository has no worktree: %w", err)
+	}
+	relPath, err := filepath.Rel(worktree.Filesystem.Root(), absPath)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve baseline %q: %w", ref, err)
+	}
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve baseline %q to a commit: %w", ref, err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "snyk-mcp-baseline-")
+	if err != nil {
+		return nil, err
+	}
+	checkout := &baselineCheckout{dir: dir, commit: commit.Hash.String(), path: filepath.Join(dir, relPath)}
+	written, err := exportTree(tree, filepath.ToSlash(relPath), dir)
+	if err != nil {
+		checkout.cleanup()
+		return nil, fmt.Errorf("failed to check out baseline %q: %w", ref, err)
+	}
+	checkout.empty = written == 0
+	return checkout, nil
+}
+
+// baselinePolicyFiles apply to all directories below them. They are exported from the directories
+// above the scanned path as well, so that policies and ignores of the repository root still apply.
+var baselinePolicyFiles = []string{".snyk", ".gitignore", ".dcignore"}
+
+// baselineLockfiles are the lockfiles a manifest needs to be scanned in the baseline checkout, which
+// has no installed dependencies to resolve the manifest from
+var baselineLockfiles = map[string][]string{
+	"package.json":  {"package-lock.json", "npm-shrinkwrap.json", "yarn.lock", "pnpm-lock.yaml"},
+	"Gemfile":       {"Gemfile.lock"},
+	"composer.json": {"composer.lock"},
+	"Pipfile":       {"Pipfile.lock"},
+}
+
+// exportTree writes the files of the tree below prefix into dir and returns how many were written.
+// Policy files of the directories above prefix are written as well, but not counted.
+// Symlinks and submodules are left out.
+func exportTree(tree *object.Tree, prefix string, dir string) (int, error) {
+	if prefix == "." {
+		prefix = ""
+	}
+	written := 0
+	err := tree.Files().ForEach(func(file *object.File) error {
+		inPrefix := prefix == "" || file.Name == prefix || strings.HasPrefix(file.Name, prefix+"/")
+		if !inPrefix && !isAncestorPolicyFile(file.Name, prefix) {
+			return nil
+		}
+		if file.Mode != filemode.Regular && file.Mode != filemode.Executable && file.Mode != filemode.Deprecated {
+			return nil
+		}
+		perm := os.FileMode(0644)
+		if file.Mode == filemode.Executable {
+			perm = 0755
+		}
+		if err := writeBlob(file, filepath.Join(dir, filepath.FromSlash(file.Name)), perm); err != nil {
+			return err
+		}
+		if inPrefix {
+			written++
+		}
+		return nil
+	})
+	return written, err
+}
+
+// isAncestorPolicyFile reports whether the file is a policy file in a directory above prefix
+func isAncestorPolicyFile(name string, prefix string) bool {
+	if !slices.Contains(baselinePolicyFiles, path.Base(name)) {
+		return false
+	}
+	fileDir := path.Dir(name)
+	return fileDir == "." || strings.HasPrefix(prefix, fileDir+"/")
+}
+
+// checkBaselineLockfiles fails for manifests without a lockfile in the directory, or below it if recursive
+func checkBaselineLockfiles(dir string, recursive bool) error {
+	return filepath.WalkDir(dir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if filePath != dir && (!recursive || entry.Name() == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		lockfiles, ok := baselineLockfiles[entry.Name()]
+		if !ok {
+			return nil
+		}
+		for _, lockfile := range lockfiles {
+			if _, statErr := os.Stat(filepath.Join(filepath.Dir(filePath), lockfile)); statErr == nil {
+				return nil
+			}
+		}
+		relPath, relErr := filepath.Rel(dir, filePath)
+		if relErr != nil {
+			relPath = filePath
+		}
+		return fmt.Errorf("%s has no committed lockfile (%s), its dependencies cannot be resolved without installing them", filepath.ToSlash(relPath), strings.Join(lockfiles, ", "))
+	})
+}
+
+func writeBlob(file *object.File, target string, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	reader, err := file.Reader()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	_, copyErr := io.Copy(out, reader)
+	return errors.Join(copyErr, out.Close())
+}
+
+// relocateArgs replaces the scanned path in the CLI arguments
+func relocateArgs(args []string, path string, relocatedPath string) []string {
+	relocated := slices.Clone(args)
+	// the first argument is the CLI binary
+	for i := 1; i < len(relocated); i++ {
+		if relocated[i] == path {
+			relocated[i] = relocatedPath
+		}
+	}
+	return relocated
+}
+
+// scanBaseline runs the tool against the scanned path as of the baseline ref and maps the issues
+func (m *McpLLMBinding) scanBaseline(ctx context.Context, invocationCtx workflow.InvocationContext, logger *zerolog.Logger, toolDef SnykMcpToolsDefinition, args []string, opts toolRunOptions) (*baselineScan, error) {
+	checkout, err := checkoutBaseline(opts.path, opts.baseline)
+	if err != nil {
+		return nil, err
+	}
+	defer checkout.cleanup()
+
+	scan := &baselineScan{ref: opts.baseline, commit: checkout.commit, issues: []types.IssueData{}}
+	if checkout.empty {
+		logger.Debug().Str("ref", opts.baseline).Str("path", opts.path).Msg("Scanned path does not exist at baseline")
+		return scan, nil
+	}
+
+	baselineWorkingDir := checkout.path
+	if info, statErr := os.Stat(checkout.path); statErr == nil && !info.IsDir() {
+		baselineWorkingDir = filepath.Dir(checkout.path)
+	}
+	if toolDef.Name == ToolName.ScaTest {
+		if err = checkBaselineLockfiles(baselineWorkingDir, slices.Contains(args, "--all-projects")); err != nil {
+			return nil, fmt.Errorf("cannot scan baseline %q: %w", opts.baseline, err)
+		}
+	}
+	output, err := m.runSnykShared(ctx, invocationCtx, toolDef.Name, baselineWorkingDir, relocateArgs(args, opts.path, checkout.path))
+	if err != nil {
+		return nil, fmt.Errorf("baseline scan of %q failed: %w", opts.baseline, err)
+	}
+	result, ok := buildEnhancedScanResult(logger, toolDef, output, true, baselineWorkingDir, opts.includeIgnores)
+	if !ok {
+		return nil, fmt.Errorf("baseline scan of %q returned unexpected output: %s", opts.baseline, output)
+	}
+	// the baseline is compared within the same files as the current result
+	scan.issues, _ = opts.filter.apply(result.Issues, baselineWorkingDir)
+	return scan, nil
+}
+
+// baselineIssueKey identifies an issue across scans. Code issues have a fingerprint that is stable
+// across line changes, SCA issues one of the vulnerable package that doesn't change with its dependency
+// paths. Other issues are identified by ID and package.
+func baselineIssueKey(issue types.IssueData) string {
+	if issue.PackageFingerPrint != "" {
+		return issue.PackageFingerPrint
+	}
+	if issue.FingerPrint != "" {
+		return issue.FingerPrint
+	}
+	return issue.ID + "\x00" + issue.PackageName
+}
+
+// applyBaseline reduces the result to the issues that are not in the baseline
+func applyBaseline(result *EnhancedScanResult, baseline *baselineScan) {
+	baselineKeys := make(map[string]bool, len(baseline.issues))
+	for _, issue := range baseline.issues {
+		baselineKeys[baselineIssueKey(issue)] = true
+	}
+
+	currentKeys := make(map[string]bool, len(result.Issues))
+	added := []types.IssueData{}
+	preExisting := 0
+	for _, issue := range result.Issues {
+		key := baselineIssueKey(issue)
+		currentKeys[key] = true
+		if baselineKeys[key] {
+			preExisting++
+			continue
+		}
+		added = append(added, issue)
+	}
+
+	fixed := 0
+	for key := range baselineKeys {
+		if !currentKeys[key] {
+			fixed++
+		}
+	}
+
+	result.Issues = added
+	result.IssueCount = len(added)
+	result.Baseline = &BaselineComparison{
+		Ref:                   baseline.ref,
+		Commit:                baseline.commit,
+		FixedIssueCount:       fixed,
+		PreExistingIssueCount: preExisting,
+	}
+}
@@ -0,0 +1,325 @@
+/*
+ * © 2025 Snyk Limited
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/studio-mcp/internal/types"
+)
+
+// commitAll stages and commits all files in the repository and returns the commit hash
+func commitAll(t *testing.T, repo *git.Repository, message string) string {
+	t.Helper()
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, worktree.AddWithOptions(&git.AddOptions{All: true}))
+	hash, err := worktree.Commit(message, &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+	return hash.String()
+}
+
+func TestCheckoutBaseline(t *testing.T) {
+	repoDir := t.TempDir()
+	repo, err := git.PlainInit(repoDir, false)
+	require.NoError(t, err)
+	writeTestFile(t, filepath.Join(repoDir, "service", "package.json"), `{"name":"v1"}`)
+	writeTestFile(t, filepath.Join(repoDir, "other", "main.go"), "package main")
+	writeTestFile(t, filepath.Join(repoDir, ".snyk"), "version: v1.25.0\n")
+	writeTestFile(t, filepath.Join(repoDir, ".gitignore"), "dist/\n")
+	writeTestFile(t, filepath.Join(repoDir, "other", ".snyk"), "version: v1.25.0\n")
+	firstCommit := commitAll(t, repo, "first")
+	writeTestFile(t, filepath.Join(repoDir, "service", "package.json"), `{"name":"v2"}`)
+	commitAll(t, repo, "second")
+	// uncommitted changes are not part of the baseline
+	writeTestFile(t, filepath.Join(repoDir, "service", "package.json"), `{"name":"v3"}`)
+	writeTestFile(t, filepath.Join(repoDir, "new", "index.js"), "console.log(1)")
+
+	t.Run("exports the scanned path at HEAD", func(t *testing.T) {
+		checkout, err := checkoutBaseline(filepath.Join(repoDir, "service"), DefaultBaselineRef)
+		require.NoError(t, err)
+		defer checkout.cleanup()
+
+		assert.False(t, checkout.empty)
+		assert.Equal(t, filepath.Join(checkout.dir, "service"), checkout.path)
+		content, err := os.ReadFile(filepath.Join(checkout.path, "package.json"))
+		require.NoError(t, err)
+		assert.Equal(t, `{"name":"v2"}`, string(content))
+		assert.NoFileExists(t, filepath.Join(checkout.dir, "other", "main.go"))
+	})
+
+	t.Run("exports an older commit", func(t *testing.T) {
+		checkout, err := checkoutBaseline(repoDir, firstCommit)
+		require.NoError(t, err)
+		defer checkout.cleanup()
+
+		assert.Equal(t, firstCommit, checkout.commit)
+		content, err := os.ReadFile(filepath.Join(checkout.path, "service", "package.json"))
+		require.NoError(t, err)
+		assert.Equal(t, `{"name":"v1"}`, string(content))
+		assert.FileExists(t, filepath.Join(checkout.path, "other", "main.go"))
+	})
+
+	t.Run("exports the policy files above the scanned path", func(t *testing.T) {
+		checkout, err := checkoutBaseline(filepath.Join(repoDir, "service"), DefaultBaselineRef)
+		require.NoError(t, err)
+		defer checkout.cleanup()
+
+		assert.FileExists(t, filepath.Join(checkout.dir, ".snyk"))
+		assert.FileExists(t, filepath.Join(checkout.dir, ".gitignore"))
+		assert.NoFileExists(t, filepath.Join(checkout.dir, "other", ".snyk"))
+	})
+
+	t.Run("path that did not exist at the baseline is empty", func(t *testing.T) {
+		checkout, err := checkoutBaseline(filepath.Join(repoDir, "new"), DefaultBaselineRef)
+		require.NoError(t, err)
+		defer checkout.cleanup()
+
+		assert.True(t, checkout.empty)
+	})
+
+	t.Run("cleanup removes the checkout", func(t *testing.T) {
+		checkout, err := checkoutBaseline(repoDir, DefaultBaselineRef)
+		require.NoError(t, err)
+		checkout.cleanup()
+
+		assert.NoDirExists(t, checkout.dir)
+	})
+
+	t.Run("unknown ref fails", func(t *testing.T) {
+		_, err := checkoutBaseline(repoDir, "does-not-exist")
+		assert.ErrorContains(t, err, "cannot resolve baseline")
+	})
+
+	t.Run("path outside of a git repository fails", func(t *testing.T) {
+		_, err := checkoutBaseline(t.TempDir(), DefaultBaselineRef)
+		assert.ErrorContains(t, err, "not in a git repository")
+	})
+}
+
+func TestCheckBaselineLockfiles(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "package.json"), `{"name":"app"}`)
+	writeTestFile(t, filepath.Join(dir, "package-lock.json"), `{}`)
+	writeTestFile(t, filepath.Join(dir, "web", "package.json"), `{"name":"web"}`)
+	writeTestFile(t, filepath.Join(dir, "node_modules", "acorn", "package.json"), `{"name":"acorn"}`)
+
+	t.Run("manifest with lockfile passes", func(t *testing.T) {
+		assert.NoError(t, checkBaselineLockfiles(dir, false))
+	})
+
+	t.Run("manifest without lockfile fails", func(t *testing.T) {
+		err := checkBaselineLockfiles(dir, true)
+		assert.ErrorContains(t, err, "web/package.json has no committed lockfile")
+	})
+
+	t.Run("node_modules is not checked", func(t *testing.T) {
+		writeTestFile(t, filepath.Join(dir, "web", "yarn.lock"), "")
+		assert.NoError(t, checkBaselineLockfiles(dir, true))
+	})
+}
+
+func TestRelocateArgs(t *testing.T) {
+	args := []string{"/usr/bin/snyk", "test", "/repo/service", "--json", "--all-projects"}
+
+	relocated := relocateArgs(args, "/repo/service", "/tmp/baseline/service")
+
+	assert.Equal(t, []string{"/usr/bin/snyk", "test", "/tmp/baseline/service", "--json", "--all-projects"}, relocated)
+	assert.Equal(t, "/repo/service", args[2], "original arguments must not be modified")
+}
+
+func TestApplyBaseline(t *testing.T) {
+	codeIssue := func(fingerprint string) types.IssueData {
+		return types.IssueData{ID: "javascript/XSS", FingerPrint: fingerprint}
+	}
+	scaIssue := func(id string, packageName string) types.IssueData {
+		return types.IssueData{ID: id, PackageName: packageName}
+	}
+
+	testCases := []struct {
+		name                string
+		current             []types.IssueData
+		baseline            []types.IssueData
+		expectedIssues      []types.IssueData
+		expectedFixed       int
+		expectedPreExisting int
+	}{
+		{
+			name:                "code issues are matched by fingerprint",
+			current:             []types.IssueData{codeIssue("a"), codeIssue("b")},
+			baseline:            []types.IssueData{codeIssue("a"), codeIssue("c")},
+			expectedIssues:      []types.IssueData{codeIssue("b")},
+			expectedFixed:       1,
+			expectedPreExisting: 1,
+		},
+		{
+			name:                "sca issues are matched by id and package",
+			current:             []types.IssueData{scaIssue("SNYK-1", "lodash"), scaIssue("SNYK-1", "lodash-es")},
+			baseline:            []types.IssueData{scaIssue("SNYK-1", "lodash"), scaIssue("SNYK-2", "acorn")},
+			expectedIssues:      []types.IssueData{scaIssue("SNYK-1", "lodash-es")},
+			expectedFixed:       1,
+			expectedPreExisting: 1,
+		},
+		{
+			name: "sca issue with an added dependency path is pre-existing",
+			current: []types.IssueData{
+				{ID: "SNYK-1", PackageName: "lodash", FingerPrint: "lodash-two-paths", PackageFingerPrint: "lodash"},
+				{ID: "SNYK-2", PackageName: "acorn", FingerPrint: "acorn-one-path", PackageFingerPrint: "acorn"},
+			},
+			baseline: []types.IssueData{
+				{ID: "SNYK-1", PackageName: "lodash", FingerPrint: "lodash-one-path", PackageFingerPrint: "lodash"},
+			},
+			expectedIssues:      []types.IssueData{{ID: "SNYK-2", PackageName: "acorn", FingerPrint: "acorn-one-path", PackageFingerPrint: "acorn"}},
+			expectedFixed:       0,
+			expectedPreExisting: 1,
+		},
+		{
+			name:           "empty baseline keeps all issues",
+			current:        []types.IssueData{codeIssue("a")},
+			baseline:       []types.IssueData{},
+			expectedIssues: []types.IssueData{codeIssue("a")},
+		},
+		{
+			name:                "no new issues",
+			current:             []types.IssueData{codeIssue("a")},
+			baseline:            []types.IssueData{codeIssue("a"), codeIssue("b")},
+			expectedIssues:      []types.IssueData{},
+			expectedFixed:       1,
+			expectedPreExisting: 1,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := EnhancedScanResult{Issues: tc.current, IssueCount: len(tc.current)}
+
+			applyBaseline(&result, &baselineScan{ref: "HEAD", commit: "abc", issues: tc.baseline})
+
+			assert.Equal(t, tc.expectedIssues, result.Issues)
+			assert.Equal(t, len(tc.expectedIssues), result.IssueCount)
+			require.NotNil(t, result.Baseline)
+			assert.Equal(t, "HEAD", result.Baseline.Ref)
+			assert.Equal(t, "abc", result.Baseline.Commit)
+			assert.Equal(t, tc.expectedFixed, result.Baseline.FixedIssueCount)
+			assert.Equal(t, tc.expectedPreExisting, result.Baseline.PreExistingIssueCount)
+		})
+	}
+}
+
+func TestDefaultHandlerBaseline(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("mock CLI script uses sh")
+	}
+	fixture := setupTestFixture(t)
+	repoDir := t.TempDir()
+	repo, err := git.PlainInit(repoDir, false)
+	require.NoError(t, err)
+	writeTestFile(t, filepath.Join(repoDir, "package.json"), `{"name":"app"}`)
+	writeTestFile(t, filepath.Join(repoDir, "package-lock.json"), `{}`)
+	writeTestFile(t, filepath.Join(repoDir, ".snyk"), "version: v1.25.0\n")
+	writeTestFile(t, filepath.Join(repoDir, "service", "package.json"), `{"name":"service"}`)
+	writeTestFile(t, filepath.Join(repoDir, "service", "package-lock.json"), `{}`)
+	commit := commitAll(t, repo, "initial")
+
+	vulnerability := func(id string, packageName string) string {
+		return fmt.Sprintf(`{"id":%q,"title":"t","severity":"high","packageName":%q,"version":"1.0.0","from":["app@1.0.0",%q],"packageManager":"npm"}`, id, packageName, packageName+"@1.0.0")
+	}
+	// acorn is also introduced through a new dependency path, which doesn't make it a new issue
+	acornThroughWebpack := `{"id":"SNYK-1","title":"t","severity":"high","packageName":"acorn","version":"1.0.0","from":["app@1.0.0","webpack@5.0.0","acorn@1.0.0"],"packageManager":"npm"}`
+	currentOutput := fmt.Sprintf(`{"ok":false,"vulnerabilities":[%s,%s,%s],"packageManager":"npm"}`, vulnerability("SNYK-1", "acorn"), acornThroughWebpack, vulnerability("SNYK-2", "lodash"))
+	baselineOutput := fmt.Sprintf(`{"ok":false,"vulnerabilities":[%s,%s],"packageManager":"npm"}`, vulnerability("SNYK-1", "acorn"), vulnerability("SNYK-3", "minimist"))
+	// the baseline is scanned in a temporary checkout, which must have the policy of the repository root
+	createMockSnykCliWithScript(t, fixture.snykCliPath, fmt.Sprintf(`#!/bin/sh
+case "$PWD" in
+  *snyk-mcp-baseline-*)
+    if [ ! -f .snyk ] && [ ! -f ../.snyk ]; then
+      echo 'missing policy' >&2
+      exit 2
+    fi
+    echo '%s' ;;
+  *) echo '%s' ;;
+esac
+exit 1
+`, baselineOutput, currentOutput))
+	tool := getToolWithName(t, fixture.tools, ToolName.ScaTest)
+	require.NotNil(t, tool)
+	handler := fixture.binding.defaultHandler(fixture.invocationContext, *tool)
+
+	t.Run("returns only new issues", func(t *testing.T) {
+		output := callToolWithArgs(t, handler, map[string]any{"path": repoDir, "baseline": ""})
+
+		var result EnhancedScanResult
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		require.Len(t, result.Issues, 1)
+		assert.Equal(t, "SNYK-2", result.Issues[0].ID)
+		assert.Equal(t, 1, result.IssueCount)
+		require.NotNil(t, result.Baseline)
+		assert.Equal(t, DefaultBaselineRef, result.Baseline.Ref)
+		assert.Equal(t, commit, result.Baseline.Commit)
+		assert.Equal(t, 1, result.Baseline.FixedIssueCount)
+		assert.Equal(t, 1, result.Baseline.PreExistingIssueCount)
+	})
+
+	t.Run("scans a subdirectory with the policy of the repository root", func(t *testing.T) {
+		output := callToolWithArgs(t, handler, map[string]any{"path": filepath.Join(repoDir, "service"), "baseline": ""})
+
+		var result EnhancedScanResult
+		require.NoError(t, json.Unmarshal([]byte(output), &result), output)
+		require.NotNil(t, result.Baseline)
+		assert.Equal(t, 1, result.IssueCount)
+		assert.Equal(t, 1, result.Baseline.FixedIssueCount)
+	})
+
+	t.Run("reports a manifest without lockfile", func(t *testing.T) {
+		unlockedDir := t.TempDir()
+		unlockedRepo, err := git.PlainInit(unlockedDir, false)
+		require.NoError(t, err)
+		writeTestFile(t, filepath.Join(unlockedDir, "package.json"), `{"name":"unlocked"}`)
+		commitAll(t, unlockedRepo, "initial")
+
+		output := callToolWithArgs(t, handler, map[string]any{"path": unlockedDir, "baseline": ""})
+
+		assert.Contains(t, output, `Error: cannot scan baseline "HEAD": package.json has no committed lockfile`)
+	})
+
+	t.Run("returns all issues without baseline", func(t *testing.T) {
+		output := callToolWithArgs(t, handler, map[string]any{"path": repoDir})
+
+		var result EnhancedScanResult
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		assert.Equal(t, 2, result.IssueCount)
+		assert.Nil(t, result.Baseline)
+	})
+
+	t.Run("reports an unknown baseline", func(t *testing.T) {
+		output := callToolWithArgs(t, handler, map[string]any{"path": repoDir, "baseline": "no-such-branch"})
+
+		assert.Contains(t, output, `Error: cannot resolve baseline "no-such-branch"`)
+	})
+}
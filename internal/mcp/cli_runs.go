@@ -0,0 +1,169 @@
+/*
+ * © 2025 Snyk Limited
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// DefaultMaxCliProcesses is the number of CLI processes that may run at the same time if not configured
+const DefaultMaxCliProcesses = 4
+
+// cliLimiter caps the number of concurrently running CLI processes.
+// A nil limiter does not limit.
+type cliLimiter struct {
+	slots chan struct{}
+}
+
+func newCliLimiter(maxProcesses int) *cliLimiter {
+	if maxProcesses <= 0 {
+		maxProcesses = DefaultMaxCliProcesses
+	}
+	return &cliLimiter{slots: make(chan struct{}, maxProcesses)}
+}
+
+// acquire blocks until a slot is free or the context is done. The returned function releases the slot.
+func (l *cliLimiter) acquire(ctx context.Context) (release func(), err error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return func() { <-l.slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+type cliRunFunc func(ctx context.Context) (string, error)
+
+// cliRun is a CLI run shared by all concurrent callers with the same key
+type cliRun struct {
+	done    chan struct{}
+	output  string
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+
+	// reporters are the progress reporters of the callers waiting for the run
+	reportersMutex sync.Mutex
+	reporters      []*progressReporter
+}
+
+// Write passes CLI output on to the progress reporters of all callers waiting for the run
+func (r *cliRun) Write(data []byte) (int, error) {
+	r.reportersMutex.Lock()
+	reporters := slices.Clone(r.reporters)
+	r.reportersMutex.Unlock()
+	for _, reporter := range reporters {
+		_, _ = reporter.Writer().Write(data)
+	}
+	return len(data), nil
+}
+
+func (r *cliRun) addReporter(reporter *progressReporter) {
+	if !reporter.enabled() {
+		return
+	}
+	r.reportersMutex.Lock()
+	defer r.reportersMutex.Unlock()
+	r.reporters = append(r.reporters, reporter)
+}
+
+func (r *cliRun) removeReporter(reporter *progressReporter) {
+	r.reportersMutex.Lock()
+	defer r.reportersMutex.Unlock()
+	r.reporters = slices.DeleteFunc(r.reporters, func(p *progressReporter) bool { return p == reporter })
+}
+
+// cliRunGroup lets concurrent identical CLI invocations share a single run.
+// The run is only cancelled once every caller waiting for it has given up.
+type cliRunGroup struct {
+	mutex sync.Mutex
+	runs  map[string]*cliRun
+}
+
+func newCliRunGroup() *cliRunGroup {
+	return &cliRunGroup{
+		runs: make(map[string]*cliRun),
+	}
+}
+
+// cliRunKey identifies identical invocations. Arguments are sorted, as flag order is not stable.
+func cliRunKey(toolName string, workingDir string, args []string) string {
+	sortedArgs := slices.Clone(args)
+	slices.Sort(sortedArgs)
+	return toolName + "\x00" + workingDir + "\x00" + strings.Join(sortedArgs, "\x00")
+}
+
+// do runs the function, or joins the run already in progress for the key.
+// The returned bool reports whether the result came from a run started by another caller.
+// The progress of the run is reported to every caller for as long as it waits.
+func (g *cliRunGroup) do(ctx context.Context, key string, run cliRunFunc) (string, bool, error) {
+	reporter := progressReporterFromContext(ctx)
+	g.mutex.Lock()
+	current, joined := g.runs[key]
+	if joined {
+		current.waiters++
+	} else {
+		// the run must outlive the caller that started it, as others may be waiting for it
+		runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		current = &cliRun{done: make(chan struct{}), waiters: 1, cancel: cancel}
+		g.runs[key] = current
+		go g.execute(contextWithProgressWriter(runCtx, current), key, current, run)
+	}
+	current.addReporter(reporter)
+	g.mutex.Unlock()
+
+	select {
+	case <-current.done:
+		return current.output, joined, current.err
+	case <-ctx.Done():
+		g.leave(key, current, reporter)
+		return "", joined, ctx.Err()
+	}
+}
+
+func (g *cliRunGroup) execute(ctx context.Context, key string, current *cliRun, run cliRunFunc) {
+	defer current.cancel()
+	current.output, current.err = run(ctx)
+
+	g.mutex.Lock()
+	if g.runs[key] == current {
+		delete(g.runs, key)
+	}
+	g.mutex.Unlock()
+	close(current.done)
+}
+
+// leave removes a waiter and cancels the run if nobody is waiting for it anymore
+func (g *cliRunGroup) leave(key string, current *cliRun, reporter *progressReporter) {
+	current.removeReporter(reporter)
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	current.waiters--
+	if current.waiters > 0 {
+		return
+	}
+	if g.runs[key] == current {
+		delete(g.runs, key)
+	}
+	current.cancel()
+}
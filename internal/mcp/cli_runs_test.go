@@ -0,0 +1,270 @@
+/*
+ * © 2025 Snyk Limited
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCliRunKey(t *testing.T) {
+	assert.Equal(t,
+		cliRunKey(ToolName.CodeTest, "/repo", []string{"snyk", "code", "test", "--sarif", "--org=a"}),
+		cliRunKey(ToolName.CodeTest, "/repo", []string{"snyk", "code", "test", "--org=a", "--sarif"}))
+	assert.NotEqual(t,
+		cliRunKey(ToolName.CodeTest, "/repo", []string{"snyk", "code", "test", "--org=a"}),
+		cliRunKey(ToolName.CodeTest, "/repo", []string{"snyk", "code", "test", "--org=b"}))
+	assert.NotEqual(t,
+		cliRunKey(ToolName.CodeTest, "/repo", []string{"snyk"}),
+		cliRunKey(ToolName.CodeTest, "/other", []string{"snyk"}))
+}
+
+func TestCliLimiter(t *testing.T) {
+	t.Run("blocks when all slots are taken", func(t *testing.T) {
+		limiter := newCliLimiter(1)
+		release, err := limiter.acquire(context.Background())
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err = limiter.acquire(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		release()
+		release, err = limiter.acquire(context.Background())
+		require.NoError(t, err)
+		release()
+	})
+
+	t.Run("non-positive size uses default", func(t *testing.T) {
+		assert.Equal(t, DefaultMaxCliProcesses, cap(newCliLimiter(0).slots))
+	})
+
+	t.Run("nil limiter does not limit", func(t *testing.T) {
+		var limiter *cliLimiter
+		release, err := limiter.acquire(context.Background())
+		require.NoError(t, err)
+		release()
+	})
+}
+
+func TestCliRunGroup(t *testing.T) {
+	t.Run("concurrent identical calls share one run", func(t *testing.T) {
+		group := newCliRunGroup()
+		var runs atomic.Int32
+		release := make(chan struct{})
+		run := func(ctx context.Context) (string, error) {
+			runs.Add(1)
+			<-release
+			return "output", nil
+		}
+
+		var wg sync.WaitGroup
+		var joinedCount atomic.Int32
+		for i := 0; i < 3; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				output, joined, err := group.do(context.Background(), "key", run)
+				assert.NoError(t, err)
+				assert.Equal(t, "output", output)
+				if joined {
+					joinedCount.Add(1)
+				}
+			}()
+		}
+		require.Eventually(t, func() bool {
+			group.mutex.Lock()
+			defer group.mutex.Unlock()
+			return group.runs["key"] != nil && group.runs["key"].waiters == 3
+		}, time.Second, time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), runs.Load())
+		assert.Equal(t, int32(2), joinedCount.Load())
+	})
+
+	t.Run("finished run is not reused", func(t *testing.T) {
+		group := newCliRunGroup()
+		var runs atomic.Int32
+		run := func(ctx context.Context) (string, error) {
+			return fmt.Sprintf("run %d", runs.Add(1)), nil
+		}
+
+		first, _, _ := group.do(context.Background(), "key", run)
+		second, _, _ := group.do(context.Background(), "key", run)
+
+		assert.Equal(t, "run 1", first)
+		assert.Equal(t, "run 2", second)
+	})
+
+	t.Run("run continues while another caller waits", func(t *testing.T) {
+		group := newCliRunGroup()
+		release := make(chan struct{})
+		run := func(ctx context.Context) (string, error) {
+			select {
+			case <-release:
+				return "output", nil
+			case <-ctx.Done():
+				return "", ctx.Err()
+			}
+		}
+		leaderCtx, cancelLeader := context.WithCancel(context.Background())
+		leaderDone := make(chan error)
+		go func() {
+			_, _, err := group.do(leaderCtx, "key", run)
+			leaderDone <- err
+		}()
+		require.Eventually(t, func() bool {
+			group.mutex.Lock()
+			defer group.mutex.Unlock()
+			return group.runs["key"] != nil
+		}, time.Second, time.Millisecond)
+
+		followerDone := make(chan string)
+		go func() {
+			output, _, _ := group.do(context.Background(), "key", run)
+			followerDone <- output
+		}()
+		require.Eventually(t, func() bool {
+			group.mutex.Lock()
+			defer group.mutex.Unlock()
+			return group.runs["key"].waiters == 2
+		}, time.Second, time.Millisecond)
+
+		cancelLeader()
+		assert.ErrorIs(t, <-leaderDone, context.Canceled)
+		close(release)
+		assert.Equal(t, "output", <-followerDone)
+	})
+
+	t.Run("progress is reported to every waiting caller", func(t *testing.T) {
+		group := newCliRunGroup()
+		firstReporter, firstSession := setupProgressReporter(t, "first")
+		secondReporter, secondSession := setupProgressReporter(t, "second")
+		lines := make(chan string)
+		written := make(chan struct{})
+		run := func(ctx context.Context) (string, error) {
+			for line := range lines {
+				_, _ = progressWriterFromContext(ctx).Write([]byte(line))
+				written <- struct{}{}
+			}
+			return "output", nil
+		}
+
+		firstDone := make(chan string)
+		go func() {
+			output, _, _ := group.do(contextWithProgressReporter(context.Background(), firstReporter), "key", run)
+			firstDone <- output
+		}()
+		require.Eventually(t, func() bool {
+			group.mutex.Lock()
+			defer group.mutex.Unlock()
+			return group.runs["key"] != nil
+		}, time.Second, time.Millisecond)
+		secondCtx, cancelSecond := context.WithCancel(contextWithProgressReporter(context.Background(), secondReporter))
+		secondDone := make(chan error)
+		go func() {
+			_, _, err := group.do(secondCtx, "key", run)
+			secondDone <- err
+		}()
+		require.Eventually(t, func() bool {
+			group.mutex.Lock()
+			defer group.mutex.Unlock()
+			return group.runs["key"].waiters == 2
+		}, time.Second, time.Millisecond)
+
+		lines <- "Testing package.json\n"
+		<-written
+		assert.Len(t, drainNotifications(firstSession), 1)
+		assert.Len(t, drainNotifications(secondSession), 1)
+
+		cancelSecond()
+		assert.ErrorIs(t, <-secondDone, context.Canceled)
+		lines <- "Uploading files\n"
+		<-written
+		close(lines)
+		assert.Equal(t, "output", <-firstDone)
+		assert.Len(t, drainNotifications(firstSession), 1)
+		assert.Empty(t, drainNotifications(secondSession))
+	})
+
+	t.Run("run is cancelled when all callers give up", func(t *testing.T) {
+		group := newCliRunGroup()
+		runCancelled := make(chan struct{})
+		run := func(ctx context.Context) (string, error) {
+			<-ctx.Done()
+			close(runCancelled)
+			return "", ctx.Err()
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, _, err := group.do(ctx, "key", run)
+
+		assert.ErrorIs(t, err, context.Canceled)
+		select {
+		case <-runCancelled:
+		case <-time.After(time.Second):
+			t.Fatal("run was not cancelled")
+		}
+	})
+}
+
+func TestDefaultHandlerSharesIdenticalScans(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("mock CLI script uses sh")
+	}
+	fixture := setupTestFixture(t)
+	counterFile := filepath.Join(t.TempDir(), "invocations")
+	createMockSnykCliWithScript(t, fixture.snykCliPath, fmt.Sprintf(`#!/bin/sh
+echo run >> '%s'
+sleep 0.5
+echo '{"ok": true}'
+exit 0
+`, counterFile))
+	tool := getToolWithName(t, fixture.tools, ToolName.ScaTest)
+	require.NotNil(t, tool)
+	handler := fixture.binding.defaultHandler(fixture.invocationContext, *tool)
+	path := t.TempDir()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			output := callToolWithArgs(t, handler, map[string]any{"path": path, "all_projects": true})
+			assert.Contains(t, output, `"success":true`)
+		}()
+	}
+	wg.Wait()
+
+	invocations, err := os.ReadFile(counterFile)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(invocations), "run"))
+}
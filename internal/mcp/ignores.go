@@ -0,0 +1,224 @@
+/*
+ * © 2025 Snyk Limited
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/snyk/go-application-framework/pkg/workflow"
+
+	"github.com/snyk/studio-mcp/internal/oss"
+	"github.com/snyk/studio-mcp/internal/policy"
+)
+
+const (
+	IgnoreActionAdd    = "add"
+	IgnoreActionList   = "list"
+	IgnoreActionRemove = "remove"
+)
+
+// IgnoreResult is the response of snyk_ignore. Ignores are all rules of the policy file after the action.
+type IgnoreResult struct {
+	PolicyFile     string              `json:"policyFile"`
+	Action         string              `json:"action"`
+	Ignores        []IgnoreEntry       `json:"ignores"`
+	RemovedCount   int                 `json:"removedCount,omitempty"`
+	IgnoreSettings *oss.IgnoreSettings `json:"ignoreSettings,omitempty"`
+}
+
+type IgnoreEntry struct {
+	policy.Ignore
+	Expired bool `json:"expired,omitempty"`
+}
+
+// ignoreSettingsStore holds the org ignore settings reported by the latest SCA or IaC scan of a directory
+type ignoreSettingsStore struct {
+	mutex    sync.RWMutex
+	settings map[string]oss.IgnoreSettings
+}
+
+func newIgnoreSettingsStore() *ignoreSettingsStore {
+	return &ignoreSettingsStore{
+		settings: make(map[string]oss.IgnoreSettings),
+	}
+}
+
+func (s *ignoreSettingsStore) put(dir string, settings oss.IgnoreSettings) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.settings[filepath.Clean(dir)] = settings
+}
+
+// get returns the settings of the directory, or of the closest scanned parent directory
+func (s *ignoreSettingsStore) get(dir string) (oss.IgnoreSettings, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	dir = filepath.Clean(dir)
+	for {
+		if settings, ok := s.settings[dir]; ok {
+			return settings, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return oss.IgnoreSettings{}, false
+		}
+		dir = parent
+	}
+}
+
+// recordIgnoreSettings remembers the ignore settings reported by SCA and IaC scans, so that snyk_ignore can respect them
+func (m *McpLLMBinding) recordIgnoreSettings(toolDef SnykMcpToolsDefinition, workDir string, output string) {
+	if toolDef.OutputMapper != ScaOutputMapper && toolDef.OutputMapper != IacOutputMapper {
+		return
+	}
+	if settings, ok := oss.ParseIgnoreSettings([]byte(output)); ok {
+		m.ignoreSettings.put(workDir, settings)
+	}
+}
+
+// parseIgnoreExpiry parses a date or RFC 3339 timestamp that must be in the future
+func parseIgnoreExpiry(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, fmt.Errorf("an expiry date is required to add an ignore")
+	}
+	expires, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		expires, err = time.Parse(time.RFC3339, value)
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid expiry date %q, use YYYY-MM-DD or RFC 3339", value)
+	}
+	if !expires.After(now) {
+		return time.Time{}, fmt.Errorf("expiry date %q is not in the future", value)
+	}
+	return expires.UTC(), nil
+}
+
+// snykIgnoreHandler adds, lists and removes ignore rules in the .snyk policy file of a project.
+// Ignores are only added if the org settings of the last SCA or IaC scan of the project allow ignores in policy files.
+// A reason and expiry are always required, which also satisfies orgs that require a reason.
+func (m *McpLLMBinding) snykIgnoreHandler(invocationCtx workflow.InvocationContext, toolDef SnykMcpToolsDefinition) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		logger := m.logger.With().Str("method", toolDef.Name).Logger()
+		logger.Debug().Str("toolName", toolDef.Name).Msg("Received call for tool")
+
+		args := request.GetArguments()
+		projectDir, err := getRequiredStringArg(args, "path")
+		if err != nil {
+			return nil, err
+		}
+		action, err := getRequiredStringArg(args, "action")
+		if err != nil {
+			return nil, err
+		}
+		action = strings.ToLower(strings.TrimSpace(action))
+
+		if trustErr := m.folderTrustError(invocationCtx, toolDef, projectDir); trustErr != "" {
+			logger.Error().Msg(trustErr)
+			return mcp.NewToolResultText(trustErr), nil
+		}
+
+		if info, statErr := os.Stat(projectDir); statErr != nil || !info.IsDir() {
+			return mcp.NewToolResultText(fmt.Sprintf("Error: path '%s' is not a project directory", projectDir)), nil
+		}
+
+		projectPolicy, err := policy.Load(projectDir)
+		if err != nil {
+			return mcp.NewToolResultText(fmt.Sprintf("Error: %s", err.Error())), nil
+		}
+
+		id := strings.TrimSpace(getOptionalStringArg(args, "id"))
+		ignorePath := strings.TrimSpace(getOptionalStringArg(args, "ignore_path"))
+		now := time.Now().UTC()
+		result := IgnoreResult{PolicyFile: projectPolicy.Path(), Action: action}
+
+		switch action {
+		case IgnoreActionList:
+		case IgnoreActionAdd:
+			if id == "" {
+				return mcp.NewToolResultText("Error: argument 'id' is required to add an ignore"), nil
+			}
+			reason := strings.TrimSpace(getOptionalStringArg(args, "reason"))
+			if reason == "" {
+				return mcp.NewToolResultText("Error: a reason is required to add an ignore"), nil
+			}
+			expires, expiryErr := parseIgnoreExpiry(getOptionalStringArg(args, "expires"), now)
+			if expiryErr != nil {
+				return mcp.NewToolResultText(fmt.Sprintf("Error: %s", expiryErr.Error())), nil
+			}
+
+			settings, known := m.ignoreSettings.get(projectDir)
+			switch {
+			case !known:
+				return mcp.NewToolResultText(fmt.Sprintf("Error: the ignore settings of the organization are not known for '%s'. Run %s or %s on it first", projectDir, ToolName.ScaTest, ToolName.IacTest)), nil
+			case settings.AdminOnly:
+				return mcp.NewToolResultText(fmt.Sprintf("Error: only administrators can ignore issues in this organization. Ask an administrator to ignore %s in the Snyk web UI", id)), nil
+			case settings.DisregardFilesystemIgnores:
+				return mcp.NewToolResultText("Error: this organization disregards ignores in .snyk policy files, ask an administrator to ignore the issue in the Snyk web UI"), nil
+			}
+			result.IgnoreSettings = &settings
+
+			if ignorePath == "" {
+				ignorePath = policy.AllPaths
+			}
+			projectPolicy.AddIgnore(policy.Ignore{ID: id, Path: ignorePath, Reason: reason, Expires: &expires, Created: &now})
+			if err = projectPolicy.Save(); err != nil {
+				return mcp.NewToolResultText(fmt.Sprintf("Error: failed to write %s: %s", projectPolicy.Path(), err.Error())), nil
+			}
+			logger.Info().Str("id", id).Str("ignorePath", ignorePath).Str("policyFile", projectPolicy.Path()).Msg("Added ignore")
+		case IgnoreActionRemove:
+			if id == "" {
+				return mcp.NewToolResultText("Error: argument 'id' is required to remove an ignore"), nil
+			}
+			result.RemovedCount = projectPolicy.RemoveIgnore(id, ignorePath)
+			if result.RemovedCount == 0 {
+				return mcp.NewToolResultText(fmt.Sprintf("Error: %s has no ignore of %s", projectPolicy.Path(), id)), nil
+			}
+			if err = projectPolicy.Save(); err != nil {
+				return mcp.NewToolResultText(fmt.Sprintf("Error: failed to write %s: %s", projectPolicy.Path(), err.Error())), nil
+			}
+			logger.Info().Str("id", id).Int("removed", result.RemovedCount).Str("policyFile", projectPolicy.Path()).Msg("Removed ignore")
+		default:
+			return mcp.NewToolResultText(fmt.Sprintf("Error: unsupported action %q, use %q, %q or %q", action, IgnoreActionAdd, IgnoreActionList, IgnoreActionRemove)), nil
+		}
+
+		ignores, err := projectPolicy.Ignores()
+		if err != nil {
+			return mcp.NewToolResultText(fmt.Sprintf("Error: %s", err.Error())), nil
+		}
+		result.Ignores = make([]IgnoreEntry, 0, len(ignores))
+		for _, ignore := range ignores {
+			result.Ignores = append(result.Ignores, IgnoreEntry{Ignore: ignore, Expired: ignore.Expires != nil && !ignore.Expires.After(now)})
+		}
+
+		resultJSON, err := json.Marshal(result)
+		if err != nil {
+			return nil, err
+		}
+		return mcp.NewToolResultText(string(resultJSON)), nil
+	}
+}
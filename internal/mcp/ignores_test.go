@@ -0,0 +1,175 @@
+/*
+ * © 2025 Snyk Limited
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/studio-mcp/internal/oss"
+	"github.com/snyk/studio-mcp/internal/policy"
+	"github.com/snyk/studio-mcp/internal/trust"
+)
+
+func TestParseIgnoreExpiry(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	expires, err := parseIgnoreExpiry("2026-06-30", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC), expires)
+
+	expires, err = parseIgnoreExpiry("2026-04-01T10:00:00+02:00", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 4, 1, 8, 0, 0, 0, time.UTC), expires)
+
+	_, err = parseIgnoreExpiry("", now)
+	assert.ErrorContains(t, err, "expiry date is required")
+	_, err = parseIgnoreExpiry("next month", now)
+	assert.ErrorContains(t, err, "invalid expiry date")
+	_, err = parseIgnoreExpiry("2026-03-01", now)
+	assert.ErrorContains(t, err, "not in the future")
+}
+
+func TestIgnoreSettingsStore(t *testing.T) {
+	store := newIgnoreSettingsStore()
+	repoDir := filepath.Join(t.TempDir(), "repo")
+	store.put(repoDir, oss.IgnoreSettings{ReasonRequired: true})
+
+	settings, ok := store.get(filepath.Join(repoDir, "services", "api"))
+	assert.True(t, ok, "settings of the scanned parent directory apply")
+	assert.True(t, settings.ReasonRequired)
+
+	_, ok = store.get(filepath.Dir(repoDir))
+	assert.False(t, ok)
+}
+
+func TestSnykIgnoreHandler(t *testing.T) {
+	fixture := setupTestFixture(t)
+	tool := getToolWithName(t, fixture.tools, ToolName.Ignore)
+	require.NotNil(t, tool)
+	require.NotNil(t, tool.Annotations.DestructiveHint)
+	assert.True(t, *tool.Annotations.DestructiveHint, "hosts must ask the user before changing ignores")
+	handler := fixture.binding.snykIgnoreHandler(fixture.invocationContext, *tool)
+	expires := time.Now().AddDate(0, 3, 0).Format(time.DateOnly)
+	addArgs := func(projectDir string) map[string]any {
+		return map[string]any{"path": projectDir, "action": IgnoreActionAdd, "id": "SNYK-JS-LODASH-567746", "reason": "not reachable", "expires": expires}
+	}
+
+	t.Run("requires known ignore settings", func(t *testing.T) {
+		projectDir := t.TempDir()
+
+		output := callToolWithArgs(t, handler, addArgs(projectDir))
+
+		assert.Contains(t, output, "Run snyk_sca_scan or snyk_iac_scan on it first")
+		assert.NoFileExists(t, filepath.Join(projectDir, policy.FileName))
+	})
+
+	t.Run("add, list and remove", func(t *testing.T) {
+		projectDir := t.TempDir()
+		fixture.binding.ignoreSettings.put(projectDir, oss.IgnoreSettings{ReasonRequired: true})
+
+		var added IgnoreResult
+		require.NoError(t, json.Unmarshal([]byte(callToolWithArgs(t, handler, addArgs(projectDir))), &added))
+		assert.Equal(t, filepath.Join(projectDir, policy.FileName), added.PolicyFile)
+		require.Len(t, added.Ignores, 1)
+		assert.Equal(t, "SNYK-JS-LODASH-567746", added.Ignores[0].ID)
+		assert.Equal(t, policy.AllPaths, added.Ignores[0].Path)
+		assert.Equal(t, "not reachable", added.Ignores[0].Reason)
+		assert.NotNil(t, added.Ignores[0].Created)
+		assert.FileExists(t, added.PolicyFile)
+
+		var listed IgnoreResult
+		require.NoError(t, json.Unmarshal([]byte(callToolWithArgs(t, handler, map[string]any{"path": projectDir, "action": IgnoreActionList})), &listed))
+		assert.Len(t, listed.Ignores, 1)
+
+		var removed IgnoreResult
+		require.NoError(t, json.Unmarshal([]byte(callToolWithArgs(t, handler, map[string]any{"path": projectDir, "action": IgnoreActionRemove, "id": "SNYK-JS-LODASH-567746"})), &removed))
+		assert.Equal(t, 1, removed.RemovedCount)
+		assert.Empty(t, removed.Ignores)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		projectDir := t.TempDir()
+		fixture.binding.ignoreSettings.put(projectDir, oss.IgnoreSettings{})
+		withoutReason := addArgs(projectDir)
+		delete(withoutReason, "reason")
+		pastExpiry := addArgs(projectDir)
+		pastExpiry["expires"] = "2020-01-01"
+
+		assert.Contains(t, callToolWithArgs(t, handler, withoutReason), "a reason is required")
+		assert.Contains(t, callToolWithArgs(t, handler, pastExpiry), "not in the future")
+		assert.Contains(t, callToolWithArgs(t, handler, map[string]any{"path": projectDir, "action": "snooze"}), "unsupported action")
+		assert.Contains(t, callToolWithArgs(t, handler, map[string]any{"path": projectDir, "action": IgnoreActionRemove, "id": "SNYK-1"}), "has no ignore of SNYK-1")
+		assert.NoFileExists(t, filepath.Join(projectDir, policy.FileName))
+	})
+
+	t.Run("org settings", func(t *testing.T) {
+		testCases := []struct {
+			name     string
+			settings oss.IgnoreSettings
+			expected string
+		}{
+			{name: "admin only", settings: oss.IgnoreSettings{AdminOnly: true}, expected: "only administrators can ignore issues"},
+			{name: "filesystem ignores disregarded", settings: oss.IgnoreSettings{DisregardFilesystemIgnores: true}, expected: "disregards ignores in .snyk policy files"},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				projectDir := t.TempDir()
+				fixture.binding.ignoreSettings.put(projectDir, tc.settings)
+
+				assert.Contains(t, callToolWithArgs(t, handler, addArgs(projectDir)), tc.expected)
+				assert.NoFileExists(t, filepath.Join(projectDir, policy.FileName))
+			})
+		}
+	})
+
+	t.Run("untrusted folder", func(t *testing.T) {
+		fixture.invocationContext.GetConfiguration().Set(trust.DisableTrustFlag, false)
+		t.Cleanup(func() { fixture.invocationContext.GetConfiguration().Set(trust.DisableTrustFlag, true) })
+
+		output := callToolWithArgs(t, handler, map[string]any{"path": t.TempDir(), "action": IgnoreActionList})
+
+		assert.Contains(t, output, "is not trusted")
+	})
+}
+
+func TestDefaultHandlerRecordsIgnoreSettings(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("mock CLI script uses sh")
+	}
+	fixture := setupTestFixture(t)
+	createMockSnykCliWithScript(t, fixture.snykCliPath, `#!/bin/sh
+echo '{"ok":true,"packageManager":"npm","vulnerabilities":[],"ignoreSettings":{"adminOnly":true,"reasonRequired":true,"disregardFilesystemIgnores":false}}'
+`)
+	tool := getToolWithName(t, fixture.tools, ToolName.ScaTest)
+	require.NotNil(t, tool)
+	projectDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "package.json"), []byte(`{}`), 0600))
+
+	callToolWithArgs(t, fixture.binding.defaultHandler(fixture.invocationContext, *tool), map[string]any{"path": projectDir})
+
+	settings, ok := fixture.binding.ignoreSettings.get(projectDir)
+	assert.True(t, ok)
+	assert.Equal(t, oss.IgnoreSettings{AdminOnly: true, ReasonRequired: true}, settings)
+}
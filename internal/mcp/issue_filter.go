@@ -0,0 +1,227 @@
+/*
+ * © 2025 Snyk Limited
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/snyk/studio-mcp/internal/types"
+)
+
+// IssueFilterSummary describes the file filter applied to a result
+type IssueFilterSummary struct {
+	Files            []string `json:"files,omitempty"`
+	ChangedOnly      bool     `json:"changedOnly,omitempty"`
+	FilteredOutCount int      `json:"filteredOutCount"`
+}
+
+// issueFilter keeps the issues located in the requested files. Globs and changed files are
+// slash-separated paths relative to the scanned directory.
+type issueFilter struct {
+	globs       []string
+	changedOnly bool
+	changed     map[string]bool
+	// changedScaDirs are the directories with changed SCA inputs. SCA issues are located at the manifest,
+	// but are often introduced by a change of the lockfile next to it.
+	changedScaDirs map[string]bool
+	// patterns are the globs as requested, for reporting
+	patterns []string
+}
+
+// newIssueFilter builds the filter for the scanned directory. It returns nil if no filter is requested.
+func newIssueFilter(workingDir string, patterns []string, changedOnly bool) (*issueFilter, error) {
+	if len(patterns) == 0 && !changedOnly {
+		return nil, nil
+	}
+	filter := &issueFilter{changedOnly: changedOnly, patterns: patterns}
+	for _, pattern := range patterns {
+		if filepath.IsAbs(pattern) {
+			relPattern, err := filepath.Rel(workingDir, pattern)
+			if err != nil {
+				return nil, err
+			}
+			pattern = relPattern
+		}
+		pattern = filepath.ToSlash(pattern)
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid file pattern %q: %w", pattern, err)
+		}
+		filter.globs = append(filter.globs, pattern)
+	}
+	if changedOnly {
+		changed, err := changedFiles(workingDir)
+		if err != nil {
+			return nil, err
+		}
+		filter.changed = changed
+		filter.changedScaDirs = scaInputDirs(changed)
+	}
+	return filter, nil
+}
+
+// scaInputDirs returns the directories of the changed manifests, lockfiles and policies
+func scaInputDirs(changed map[string]bool) map[string]bool {
+	dirs := map[string]bool{}
+	for relPath := range changed {
+		if isScaInputFile(path.Base(relPath)) {
+			dirs[path.Dir(relPath)] = true
+		}
+	}
+	return dirs
+}
+
+// changedFiles returns the files below workingDir that differ from HEAD, including untracked files
+func changedFiles(workingDir string) (map[string]bool, error) {
+	absDir, err := filepath.Abs(workingDir)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := git.PlainOpenWithOptions(absDir, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, fmt.Errorf("cannot determine changed files, %s is not in a git repository: %w", workingDir, err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("cannot determine changed files, repository has no worktree: %w", err)
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return nil, fmt.Errorf("cannot determine changed files: %w", err)
+	}
+
+	changed := map[string]bool{}
+	root := worktree.Filesystem.Root()
+	for file, fileStatus := range status {
+		if fileStatus.Staging == git.Unmodified && fileStatus.Worktree == git.Unmodified {
+			continue
+		}
+		relPath, relErr := filepath.Rel(absDir, filepath.Join(root, filepath.FromSlash(file)))
+		if relErr != nil || isOutsideDir(relPath) {
+			continue
+		}
+		changed[filepath.ToSlash(relPath)] = true
+	}
+	return changed, nil
+}
+
+func isOutsideDir(relPath string) bool {
+	return relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator))
+}
+
+// isChanged reports whether the file, relative to the scanned directory, changed. An SCA input counts as
+// changed if any SCA input in its directory changed, so that a lockfile change keeps the issues of its manifest.
+func (f *issueFilter) isChanged(relPath string) bool {
+	return f.changed[relPath] || isScaInputFile(path.Base(relPath)) && f.changedScaDirs[path.Dir(relPath)]
+}
+
+// matchesFile reports whether an issue location, relative to the scanned directory, passes the filter
+func (f *issueFilter) matchesFile(relPath string) bool {
+	if f.changedOnly && !f.isChanged(relPath) {
+		return false
+	}
+	if len(f.globs) == 0 {
+		return true
+	}
+	for _, glob := range f.globs {
+		if matchGlob(glob, relPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches reports whether the issue or any step of its data flow is located in a requested file
+func (f *issueFilter) matches(issue types.IssueData, rootDir string) bool {
+	locations := []string{issue.FilePath}
+	for _, element := range issue.Dataflow {
+		locations = append(locations, string(element.FilePath))
+	}
+	for _, location := range locations {
+		if location == "" {
+			continue
+		}
+		if !filepath.IsAbs(location) {
+			location = filepath.Join(rootDir, location)
+		}
+		relPath, err := filepath.Rel(rootDir, location)
+		if err != nil || isOutsideDir(relPath) {
+			continue
+		}
+		if f.matchesFile(filepath.ToSlash(relPath)) {
+			return true
+		}
+	}
+	return false
+}
+
+// apply returns the issues that pass the filter and how many were filtered out
+func (f *issueFilter) apply(issues []types.IssueData, rootDir string) ([]types.IssueData, int) {
+	if f == nil {
+		return issues, 0
+	}
+	kept := []types.IssueData{}
+	for _, issue := range issues {
+		if f.matches(issue, rootDir) {
+			kept = append(kept, issue)
+		}
+	}
+	return kept, len(issues) - len(kept)
+}
+
+// applyIssueFilter reduces the result to the issues in the requested files
+func applyIssueFilter(result *EnhancedScanResult, filter *issueFilter, rootDir string) {
+	if filter == nil {
+		return
+	}
+	var filteredOut int
+	result.Issues, filteredOut = filter.apply(result.Issues, rootDir)
+	result.IssueCount = len(result.Issues)
+	result.Filter = &IssueFilterSummary{Files: filter.patterns, ChangedOnly: filter.changedOnly, FilteredOutCount: filteredOut}
+}
+
+// matchGlob matches a slash-separated path against a glob. `**` matches any number of directories.
+// A glob without a slash matches the file name in any directory.
+func matchGlob(glob string, name string) bool {
+	if !strings.Contains(glob, "/") {
+		matched, _ := path.Match(glob, path.Base(name))
+		return matched
+	}
+	return matchSegments(strings.Split(glob, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(globSegments []string, nameSegments []string) bool {
+	if len(globSegments) == 0 {
+		return len(nameSegments) == 0
+	}
+	if globSegments[0] == "**" {
+		for i := 0; i <= len(nameSegments); i++ {
+			if matchSegments(globSegments[1:], nameSegments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(nameSegments) == 0 {
+		return false
+	}
+	matched, _ := path.Match(globSegments[0], nameSegments[0])
+	return matched && matchSegments(globSegments[1:], nameSegments[1:])
+}
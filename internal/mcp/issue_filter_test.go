@@ -0,0 +1,190 @@
+/*
+ * © 2025 Snyk Limited
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/studio-mcp/internal/types"
+)
+
+func TestMatchGlob(t *testing.T) {
+	testCases := []struct {
+		glob     string
+		name     string
+		expected bool
+	}{
+		{glob: "src/app.js", name: "src/app.js", expected: true},
+		{glob: "src/app.js", name: "lib/src/app.js", expected: false},
+		{glob: "*.js", name: "src/deep/app.js", expected: true},
+		{glob: "src/*.js", name: "src/deep/app.js", expected: false},
+		{glob: "src/**/*.js", name: "src/deep/app.js", expected: true},
+		{glob: "src/**/*.js", name: "src/app.js", expected: true},
+		{glob: "**/package.json", name: "package.json", expected: true},
+		{glob: "**/package.json", name: "a/b/package.json", expected: true},
+		{glob: "src/**", name: "test/app.js", expected: false},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s matches %s", tc.glob, tc.name), func(t *testing.T) {
+			assert.Equal(t, tc.expected, matchGlob(tc.glob, tc.name))
+		})
+	}
+}
+
+func TestApplyIssueFilter(t *testing.T) {
+	rootDir := filepath.Join(t.TempDir(), "repo")
+	inFile := func(id string, relPath string) types.IssueData {
+		return types.IssueData{ID: id, FilePath: filepath.Join(rootDir, relPath)}
+	}
+	withFlow := inFile("flow", "src/db.js")
+	withFlow.Dataflow = []types.DataflowElement{{FilePath: types.FilePath(filepath.Join(rootDir, "src", "api", "handler.js"))}}
+	issues := []types.IssueData{inFile("app", "src/app.js"), inFile("test", "test/app.test.js"), withFlow}
+
+	t.Run("keeps issues in matching files", func(t *testing.T) {
+		filter, err := newIssueFilter(rootDir, []string{"src/app.js", filepath.Join(rootDir, "src", "api", "*.js")}, false)
+		require.NoError(t, err)
+		result := EnhancedScanResult{Issues: issues, IssueCount: len(issues)}
+
+		applyIssueFilter(&result, filter, rootDir)
+
+		assert.Equal(t, []string{"app", "flow"}, issueIDs(result.Issues))
+		assert.Equal(t, 2, result.IssueCount)
+		require.NotNil(t, result.Filter)
+		assert.Equal(t, 1, result.Filter.FilteredOutCount)
+	})
+
+	t.Run("changed files", func(t *testing.T) {
+		filter := &issueFilter{changedOnly: true, changed: map[string]bool{"test/app.test.js": true}}
+
+		kept, filteredOut := filter.apply(issues, rootDir)
+
+		assert.Equal(t, []string{"test"}, issueIDs(kept))
+		assert.Equal(t, 2, filteredOut)
+	})
+
+	t.Run("changed lockfile keeps issues of its manifest", func(t *testing.T) {
+		changed := map[string]bool{"web/package-lock.json": true, "src/app.js": true}
+		filter := &issueFilter{changedOnly: true, changed: changed, changedScaDirs: scaInputDirs(changed)}
+		scaIssues := []types.IssueData{inFile("web", "web/package.json"), inFile("api", "api/package.json"), inFile("app", "src/app.js")}
+
+		kept, filteredOut := filter.apply(scaIssues, rootDir)
+
+		assert.Equal(t, []string{"web", "app"}, issueIDs(kept))
+		assert.Equal(t, 1, filteredOut)
+	})
+
+	t.Run("no filter", func(t *testing.T) {
+		filter, err := newIssueFilter(rootDir, nil, false)
+		require.NoError(t, err)
+		result := EnhancedScanResult{Issues: issues, IssueCount: len(issues)}
+
+		applyIssueFilter(&result, filter, rootDir)
+
+		assert.Len(t, result.Issues, 3)
+		assert.Nil(t, result.Filter)
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		_, err := newIssueFilter(rootDir, []string{"src/[.js"}, false)
+		assert.ErrorContains(t, err, "invalid file pattern")
+	})
+}
+
+func TestChangedFiles(t *testing.T) {
+	repoDir := t.TempDir()
+	repo, err := git.PlainInit(repoDir, false)
+	require.NoError(t, err)
+	writeTestFile(t, filepath.Join(repoDir, "service", "index.js"), "v1")
+	writeTestFile(t, filepath.Join(repoDir, "service", "unchanged.js"), "v1")
+	writeTestFile(t, filepath.Join(repoDir, "other", "main.go"), "v1")
+	commitAll(t, repo, "initial")
+	writeTestFile(t, filepath.Join(repoDir, "service", "index.js"), "v2")
+	writeTestFile(t, filepath.Join(repoDir, "service", "lib", "new.js"), "v1")
+	writeTestFile(t, filepath.Join(repoDir, "other", "main.go"), "v2")
+
+	changed, err := changedFiles(filepath.Join(repoDir, "service"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"index.js": true, "lib/new.js": true}, changed)
+
+	_, err = changedFiles(t.TempDir())
+	assert.ErrorContains(t, err, "not in a git repository")
+}
+
+func TestDefaultHandlerFileFilter(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("mock CLI script uses sh")
+	}
+	fixture := setupTestFixture(t)
+	repoDir := t.TempDir()
+	repo, err := git.PlainInit(repoDir, false)
+	require.NoError(t, err)
+	writeTestFile(t, filepath.Join(repoDir, "app", "package.json"), `{"name":"app"}`)
+	writeTestFile(t, filepath.Join(repoDir, "web", "package.json"), `{"name":"web","dependencies":{"lodash":"^4.17.0"}}`)
+	writeTestFile(t, filepath.Join(repoDir, "web", "package-lock.json"), `{"name":"web","packages":{"node_modules/lodash":{"version":"4.17.21"}}}`)
+	commitAll(t, repo, "initial")
+	// only the lockfile changes, as when a new vulnerable version is resolved
+	writeTestFile(t, filepath.Join(repoDir, "web", "package-lock.json"), `{"name":"web","packages":{"node_modules/lodash":{"version":"4.17.0"}}}`)
+
+	project := func(targetFile string, id string) string {
+		return fmt.Sprintf(`{"ok":false,"displayTargetFile":%q,"packageManager":"npm","vulnerabilities":[{"id":%q,"title":"t","severity":"high","packageName":"lodash","version":"4.17.0","from":["app@1.0.0","lodash@4.17.0"],"packageManager":"npm"}]}`, targetFile, id)
+	}
+	createMockSnykCliWithScript(t, fixture.snykCliPath, fmt.Sprintf(`#!/bin/sh
+echo '[%s,%s]'
+exit 1
+`, project("app/package.json", "SNYK-1"), project("web/package.json", "SNYK-2")))
+	tool := getToolWithName(t, fixture.tools, ToolName.ScaTest)
+	require.NotNil(t, tool)
+	handler := fixture.binding.defaultHandler(fixture.invocationContext, *tool)
+
+	t.Run("files", func(t *testing.T) {
+		output := callToolWithArgs(t, handler, map[string]any{"path": repoDir, "all_projects": true, "files": []any{"app/**"}})
+
+		var result EnhancedScanResult
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		assert.Equal(t, []string{"SNYK-1"}, issueIDs(result.Issues))
+		require.NotNil(t, result.Filter)
+		assert.Equal(t, 1, result.Filter.FilteredOutCount)
+		require.Len(t, result.Projects, 2)
+		assert.Equal(t, 1, result.Projects[0].IssueCount)
+		assert.Zero(t, result.Projects[1].IssueCount, "counts match the filtered issues")
+
+		scan, found := fixture.binding.scanResults.get(strings.TrimPrefix(result.ResourceURI, scanResourcePrefix))
+		require.True(t, found)
+		assert.Equal(t, []string{"SNYK-1", "SNYK-2"}, issueIDs(scan.Result.Issues), "the resource holds all issues")
+		require.Len(t, scan.Result.Projects, 2)
+		assert.Equal(t, 1, scan.Result.Projects[1].IssueCount)
+	})
+
+	t.Run("changed only", func(t *testing.T) {
+		output := callToolWithArgs(t, handler, map[string]any{"path": repoDir, "all_projects": true, "changed_only": true})
+
+		var result EnhancedScanResult
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		assert.Equal(t, []string{"SNYK-2"}, issueIDs(result.Issues))
+		require.NotNil(t, result.Filter)
+		assert.True(t, result.Filter.ChangedOnly)
+	})
+}
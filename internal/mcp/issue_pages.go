@@ -0,0 +1,199 @@
+/*
+ * © 2025 Snyk Limited
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/snyk/studio-mcp/internal/types"
+)
+
+const (
+	// DefaultMaxIssues is the number of issues returned per page if not requested otherwise
+	DefaultMaxIssues = 20
+	// issuePageRetention is how long the remaining pages of a result can be fetched
+	issuePageRetention = time.Hour
+	// summaryMaxFiles is the number of files with the most issues listed in the summary
+	summaryMaxFiles = 20
+)
+
+var errInvalidCursor = errors.New("invalid or expired cursor, run the scan again without cursor")
+
+// IssueSummary counts all issues of a result, including those not on the current page
+type IssueSummary struct {
+	BySeverity map[string]int   `json:"bySeverity"`
+	ByFile     []FileIssueCount `json:"byFile,omitempty"`
+}
+
+type FileIssueCount struct {
+	File       string `json:"file"`
+	IssueCount int    `json:"issueCount"`
+}
+
+// IssuePage describes which of the issues of a result are returned
+type IssuePage struct {
+	Offset     int    `json:"offset"`
+	Returned   int    `json:"returned"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+type pagedResult struct {
+	toolName  string
+	result    EnhancedScanResult
+	createdAt time.Time
+}
+
+// issuePageStore keeps results with more issues than fit on a page, so later pages can be served
+// without running the scan again
+type issuePageStore struct {
+	mutex   sync.RWMutex
+	results map[string]*pagedResult
+}
+
+func newIssuePageStore() *issuePageStore {
+	return &issuePageStore{results: make(map[string]*pagedResult)}
+}
+
+func (s *issuePageStore) put(toolName string, result EnhancedScanResult) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	cutoff := time.Now().Add(-issuePageRetention)
+	for id, stored := range s.results {
+		if stored.createdAt.Before(cutoff) {
+			delete(s.results, id)
+		}
+	}
+	id := uuid.NewString()
+	s.results[id] = &pagedResult{toolName: toolName, result: result, createdAt: time.Now()}
+	return id
+}
+
+func (s *issuePageStore) get(id string) (*pagedResult, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	stored, ok := s.results[id]
+	if !ok || time.Since(stored.createdAt) > issuePageRetention {
+		return nil, false
+	}
+	return stored, true
+}
+
+func encodeCursor(resultID string, offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(resultID + ":" + strconv.Itoa(offset)))
+}
+
+func decodeCursor(cursor string) (string, int, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", 0, errInvalidCursor
+	}
+	resultID, offsetString, found := strings.Cut(string(decoded), ":")
+	offset, err := strconv.Atoi(offsetString)
+	if !found || err != nil || offset < 0 {
+		return "", 0, errInvalidCursor
+	}
+	return resultID, offset, nil
+}
+
+// sortBySeverity orders the issues from critical to low, keeping the scanner's order within a severity
+func sortBySeverity(issues []types.IssueData) []types.IssueData {
+	sorted := slices.Clone(issues)
+	slices.SortStableFunc(sorted, func(a, b types.IssueData) int {
+		return types.SeverityRank(a.Severity) - types.SeverityRank(b.Severity)
+	})
+	return sorted
+}
+
+func summarizeIssues(issues []types.IssueData) *IssueSummary {
+	summary := &IssueSummary{BySeverity: map[string]int{}}
+	fileCounts := map[string]int{}
+	for _, issue := range issues {
+		summary.BySeverity[strings.ToLower(issue.Severity)]++
+		if issue.FilePath != "" {
+			fileCounts[issue.FilePath]++
+		}
+	}
+	for file, count := range fileCounts {
+		summary.ByFile = append(summary.ByFile, FileIssueCount{File: file, IssueCount: count})
+	}
+	slices.SortFunc(summary.ByFile, func(a, b FileIssueCount) int {
+		if a.IssueCount != b.IssueCount {
+			return b.IssueCount - a.IssueCount
+		}
+		return strings.Compare(a.File, b.File)
+	})
+	if len(summary.ByFile) > summaryMaxFiles {
+		summary.ByFile = summary.ByFile[:summaryMaxFiles]
+	}
+	return summary
+}
+
+// issuePage returns a copy of the result with only the issues from offset on, at most limit.
+// resultID identifies the stored result for the next cursor.
+func issuePage(result EnhancedScanResult, resultID string, offset int, limit int) EnhancedScanResult {
+	page := result
+	end := min(offset+limit, len(result.Issues))
+	start := min(offset, end)
+	page.Issues = result.Issues[start:end]
+	page.Page = &IssuePage{Offset: start, Returned: end - start}
+	if end < len(result.Issues) {
+		page.Page.NextCursor = encodeCursor(resultID, end)
+	}
+	return page
+}
+
+// paginateResult sorts the issues by severity, adds a summary and keeps only the first page.
+// If there are more issues, the result is stored for the next pages.
+func (m *McpLLMBinding) paginateResult(toolName string, result *EnhancedScanResult, maxIssues int) {
+	if maxIssues <= 0 {
+		return
+	}
+	result.Issues = sortBySeverity(result.Issues)
+	result.Summary = summarizeIssues(result.Issues)
+
+	resultID := ""
+	if len(result.Issues) > maxIssues {
+		resultID = m.issuePages.put(toolName, *result)
+	}
+	*result = issuePage(*result, resultID, 0, maxIssues)
+}
+
+// nextIssuePage returns the page of a stored result that the cursor points to
+func (m *McpLLMBinding) nextIssuePage(toolDef SnykMcpToolsDefinition, cursor string, maxIssues int) (*mcp.CallToolResult, error) {
+	resultID, offset, err := decodeCursor(cursor)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("Error: %s", err.Error())), nil
+	}
+	stored, ok := m.issuePages.get(resultID)
+	if !ok || stored.toolName != toolDef.Name {
+		return mcp.NewToolResultText(fmt.Sprintf("Error: %s", errInvalidCursor.Error())), nil
+	}
+	if maxIssues <= 0 {
+		maxIssues = DefaultMaxIssues
+	}
+	page := issuePage(stored.result, resultID, offset, maxIssues)
+	return mcp.NewToolResultText(marshalEnhancedScanResult(page, "")), nil
+}
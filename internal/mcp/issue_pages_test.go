@@ -0,0 +1,149 @@
+/*
+ * © 2025 Snyk Limited
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/studio-mcp/internal/types"
+)
+
+func TestCursor(t *testing.T) {
+	resultID, offset, err := decodeCursor(encodeCursor("abc-123", 40))
+	require.NoError(t, err)
+	assert.Equal(t, "abc-123", resultID)
+	assert.Equal(t, 40, offset)
+
+	for _, cursor := range []string{"not base64!", encodeCursor("abc", -1), "YWJj"} {
+		_, _, err = decodeCursor(cursor)
+		assert.ErrorIs(t, err, errInvalidCursor, cursor)
+	}
+}
+
+func TestSummarizeIssues(t *testing.T) {
+	issues := []types.IssueData{
+		{ID: "1", Severity: "high", FilePath: "b.js"},
+		{ID: "2", Severity: "low", FilePath: "a.js"},
+		{ID: "3", Severity: "High", FilePath: "b.js"},
+		{ID: "4", Severity: "critical"},
+	}
+
+	summary := summarizeIssues(issues)
+
+	assert.Equal(t, map[string]int{"critical": 1, "high": 2, "low": 1}, summary.BySeverity)
+	assert.Equal(t, []FileIssueCount{{File: "b.js", IssueCount: 2}, {File: "a.js", IssueCount: 1}}, summary.ByFile)
+}
+
+func TestPaginateResult(t *testing.T) {
+	binding := NewMcpLLMBinding()
+	severities := []string{"low", "critical", "medium", "high", "low"}
+	issues := []types.IssueData{}
+	for i, severity := range severities {
+		issues = append(issues, types.IssueData{ID: fmt.Sprintf("SNYK-%d", i), Severity: severity})
+	}
+	result := EnhancedScanResult{Success: true, IssueCount: len(issues), Issues: issues}
+	toolDef := SnykMcpToolsDefinition{Name: ToolName.ScaTest}
+
+	binding.paginateResult(toolDef.Name, &result, 2)
+
+	assert.Equal(t, 5, result.IssueCount)
+	assert.Equal(t, []string{"SNYK-1", "SNYK-3"}, issueIDs(result.Issues))
+	require.NotNil(t, result.Summary)
+	assert.Equal(t, 2, result.Summary.BySeverity["low"])
+	require.NotNil(t, result.Page)
+	assert.Equal(t, 0, result.Page.Offset)
+	assert.Equal(t, 2, result.Page.Returned)
+	require.NotEmpty(t, result.Page.NextCursor)
+
+	t.Run("serves the next pages from the stored result", func(t *testing.T) {
+		var ids []string
+		cursor := result.Page.NextCursor
+		for cursor != "" {
+			toolResult, err := binding.nextIssuePage(toolDef, cursor, 2)
+			require.NoError(t, err)
+			var page EnhancedScanResult
+			require.NoError(t, json.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &page))
+			assert.Equal(t, 5, page.IssueCount)
+			ids = append(ids, issueIDs(page.Issues)...)
+			cursor = page.Page.NextCursor
+		}
+		assert.Equal(t, []string{"SNYK-2", "SNYK-0", "SNYK-4"}, ids)
+	})
+
+	t.Run("rejects the cursor for another tool", func(t *testing.T) {
+		toolResult, err := binding.nextIssuePage(SnykMcpToolsDefinition{Name: ToolName.CodeTest}, result.Page.NextCursor, 2)
+		require.NoError(t, err)
+		assert.Contains(t, toolResult.Content[0].(mcp.TextContent).Text, errInvalidCursor.Error())
+	})
+
+	t.Run("small results are not stored", func(t *testing.T) {
+		small := EnhancedScanResult{Issues: issues[:2], IssueCount: 2}
+		binding.paginateResult(toolDef.Name, &small, 2)
+
+		assert.Len(t, small.Issues, 2)
+		assert.Empty(t, small.Page.NextCursor)
+		assert.Len(t, binding.issuePages.results, 1)
+	})
+}
+
+func issueIDs(issues []types.IssueData) []string {
+	ids := []string{}
+	for _, issue := range issues {
+		ids = append(ids, issue.ID)
+	}
+	return ids
+}
+
+func TestDefaultHandlerPagination(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("mock CLI script uses sh")
+	}
+	fixture := setupTestFixture(t)
+	vulnerabilities := []string{}
+	for i := range 30 {
+		vulnerabilities = append(vulnerabilities, fmt.Sprintf(`{"id":"SNYK-%d","title":"t","severity":"medium","packageName":"pkg%d","version":"1.0.0","from":["app@1.0.0","pkg%d@1.0.0"],"packageManager":"npm"}`, i, i, i))
+	}
+	createMockSnykCliWithScript(t, fixture.snykCliPath, fmt.Sprintf(`#!/bin/sh
+echo '{"ok":false,"vulnerabilities":[%s],"packageManager":"npm"}'
+exit 1
+`, strings.Join(vulnerabilities, ",")))
+	tool := getToolWithName(t, fixture.tools, ToolName.ScaTest)
+	require.NotNil(t, tool)
+	handler := fixture.binding.defaultHandler(fixture.invocationContext, *tool)
+	path := t.TempDir()
+
+	var first EnhancedScanResult
+	require.NoError(t, json.Unmarshal([]byte(callToolWithArgs(t, handler, map[string]any{"path": path})), &first))
+	assert.Equal(t, 30, first.IssueCount)
+	assert.Len(t, first.Issues, DefaultMaxIssues)
+	assert.Equal(t, 30, first.Summary.BySeverity["medium"])
+	require.NotEmpty(t, first.Page.NextCursor)
+
+	var second EnhancedScanResult
+	require.NoError(t, json.Unmarshal([]byte(callToolWithArgs(t, handler, map[string]any{"path": path, "cursor": first.Page.NextCursor, "max_issues": 5})), &second))
+	assert.Len(t, second.Issues, 5)
+	assert.Equal(t, DefaultMaxIssues, second.Page.Offset)
+	assert.NotEmpty(t, second.Page.NextCursor)
+}
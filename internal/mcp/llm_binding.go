@@ -23,10 +23,13 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"os/signal"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
+	"github.com/adrg/xdg"
 	"github.com/google/uuid"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/pkg/errors"
@@ -37,6 +40,7 @@ import (
 	"github.com/snyk/studio-mcp/internal/networking"
 	"github.com/snyk/studio-mcp/internal/trust"
 	"github.com/snyk/studio-mcp/internal/types"
+	"github.com/snyk/studio-mcp/shared"
 	"golang.org/x/exp/slices"
 	"golang.org/x/oauth2"
 
@@ -48,6 +52,14 @@ const (
 	TransportParam     string = "transport"
 	SseTransportType   string = "sse"
 	StdioTransportType string = "stdio"
+	HttpTransportType  string = "http"
+
+	// httpEndpointPath is the path the Streamable HTTP transport is served on
+	httpEndpointPath = "/mcp"
+	// httpSessionIdHeader carries the session ID of Streamable HTTP requests
+	httpSessionIdHeader = "Mcp-Session-Id"
+	// httpHeartbeatInterval keeps idle GET streams from being closed by intermediaries
+	httpHeartbeatInterval = 30 * time.Second
 )
 
 // McpLLMBinding is an implementation of a mcp server that allows interaction between
@@ -56,12 +68,23 @@ type McpLLMBinding struct {
 	logger          *zerolog.Logger
 	mcpServer       *server.MCPServer
 	sseServer       *server.SSEServer
+	httpServer      *server.StreamableHTTPServer
 	folderTrust     *trust.FolderTrust
 	baseURL         *url.URL
 	mutex           sync.RWMutex
 	started         bool
 	cliPath         string
+	authToken       string
 	openBrowserFunc types.OpenBrowserFunc
+	scanResults     *scanResultStore
+	scanJobs        *scanJobRunner
+	cliRuns         *cliRunGroup
+	cliLimiter      *cliLimiter
+	resultCache     *resultCache
+	toolCalls       *toolCallTracker
+	subscriptions   *resourceSubscriptions
+	issuePages      *issuePageStore
+	ignoreSettings  *ignoreSettingsStore
 }
 
 func NewMcpLLMBinding(opts ...Option) *McpLLMBinding {
@@ -69,6 +92,13 @@ func NewMcpLLMBinding(opts ...Option) *McpLLMBinding {
 	mcpServerImpl := &McpLLMBinding{
 		logger:          &logger,
 		openBrowserFunc: types.DefaultOpenBrowserFunc,
+		scanResults:     newScanResultStore(),
+		scanJobs:        newScanJobRunner(scanJobWorkers, scanJobQueueSize),
+		cliRuns:         newCliRunGroup(),
+		toolCalls:       newToolCallTracker(),
+		subscriptions:   newResourceSubscriptions(),
+		issuePages:      newIssuePageStore(),
+		ignoreSettings:  newIgnoreSettingsStore(),
 	}
 
 	for _, opt := range opts {
@@ -86,19 +116,35 @@ func (m *McpLLMBinding) Start(invocationContext workflow.InvocationContext) erro
 		version = runTimeInfo.GetVersion()
 	}
 
+	hooks := m.toolCalls.hooks()
+	hooks.AddOnUnregisterSession(func(_ context.Context, session server.ClientSession) {
+		// Streamable HTTP sessions outlive their GET streams, they end with the session manager
+		if !strings.HasPrefix(session.SessionID(), sessionIdPrefix) {
+			m.subscriptions.removeSession(session.SessionID())
+		}
+	})
 	m.mcpServer = server.NewMCPServer(
 		"Snyk MCP Server",
 		version,
 		server.WithLogging(),
 		server.WithResourceCapabilities(true, true),
 		server.WithPromptCapabilities(true),
+		server.WithHooks(hooks),
+		server.WithToolHandlerMiddleware(m.toolCalls.middleware),
 	)
+	m.mcpServer.AddNotificationHandler(cancelledNotificationMethod, m.toolCalls.handleCancelled)
 
 	oldLogger := invocationContext.GetEngine().GetLogger()
 	m.logger = logging.ConfigureLogging(m.mcpServer, oldLogger)
 	invocationContext.GetEngine().SetLogger(m.logger)
 
 	m.folderTrust = trust.NewFolderTrust(m.logger, invocationContext.GetConfiguration())
+	m.cliLimiter = newCliLimiter(invocationContext.GetConfiguration().GetInt(shared.MaxCliProcessesParam))
+	if cache, cacheErr := newDefaultResultCache(); cacheErr == nil {
+		m.resultCache = cache
+	} else {
+		m.logger.Warn().Err(cacheErr).Msg("Scan result cache not available")
+	}
 
 	profileStr := invocationContext.GetConfiguration().GetString(ProfileFlagName)
 	profile, err := GetProfile(profileStr)
@@ -111,13 +157,35 @@ func (m *McpLLMBinding) Start(invocationContext workflow.InvocationContext) erro
 	if err != nil {
 		return err
 	}
+	m.addScanResources()
+	m.addSnykPrompts()
 
 	transportType := invocationContext.GetConfiguration().GetString(TransportParam)
+	if transportType != StdioTransportType && m.authToken == "" {
+		token, generated, tokenErr := resolveAuthToken(invocationContext.GetConfiguration())
+		if tokenErr != nil {
+			return tokenErr
+		}
+		if generated {
+			tokenPath, pathErr := xdg.ConfigFile(authTokenFile)
+			if pathErr != nil {
+				return pathErr
+			}
+			if writeErr := writeAuthTokenFile(tokenPath, token); writeErr != nil {
+				return writeErr
+			}
+			_, _ = fmt.Fprintf(os.Stderr, "Snyk MCP server bearer token written to %s\n", tokenPath)
+		}
+		m.authToken = token
+	}
+
 	switch transportType {
 	case StdioTransportType:
 		return m.HandleStdioServer()
 	case SseTransportType:
 		return m.HandleSseServer()
+	case HttpTransportType:
+		return m.HandleHttpServer()
 	default:
 		return fmt.Errorf("invalid transport type: %s", transportType)
 	}
@@ -128,7 +196,21 @@ func (m *McpLLMBinding) HandleStdioServer() error {
 	m.started = true
 	m.mutex.Unlock()
 	m.logger.Info().Msg("Starting MCP Stdio server")
-	err := server.ServeStdio(m.mcpServer)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGTERM, syscall.SIGINT)
+	defer signal.Stop(sigChan)
+	go func() {
+		select {
+		case <-sigChan:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	err := serveStdio(ctx, m.mcpServer, m.subscriptions, os.Stdin, os.Stdout)
 
 	if err != nil {
 		m.logger.Error().Err(err).Msg("Error starting MCP Stdio server")
@@ -153,22 +235,14 @@ func (m *McpLLMBinding) HandleSseServer() error {
 	endpoint := m.baseURL.String() + "/sse"
 
 	m.logger.Info().Str("baseURL", endpoint).Msg("starting")
-	go func() {
-		// sleep initially for a few milliseconds so we actually can start the server
-		time.Sleep(100 * time.Millisecond)
-		for !networking.IsPortInUse(m.baseURL) {
-			time.Sleep(10 * time.Millisecond)
-		}
-
-		m.mutex.Lock()
-		m.logger.Info().Str("baseURL", endpoint).Msg("started")
-		m.started = true
-		m.mutex.Unlock()
-	}()
+	go m.markStartedWhenListening(endpoint)
 
+	handler := m.subscriptions.middleware(m.sseServer, func(r *http.Request) string {
+		return r.URL.Query().Get("sessionId")
+	})
 	srv := &http.Server{
 		Addr:    m.baseURL.Host,
-		Handler: middleware(m.sseServer),
+		Handler: middleware(handler, m.authToken),
 	}
 
 	err := srv.ListenAndServe()
@@ -183,13 +257,91 @@ func (m *McpLLMBinding) HandleSseServer() error {
 	return nil
 }
 
-func middleware(sseServer *server.SSEServer) http.Handler {
+// HandleHttpServer serves the MCP server using the Streamable HTTP transport.
+// Sessions are stateful, so a client can reconnect with its Mcp-Session-Id after
+// a dropped connection and keep using its session. Messages are not replayed.
+func (m *McpLLMBinding) HandleHttpServer() error {
+	// listen on default url/port if none was configured
+	if m.baseURL == nil {
+		defaultUrl, err := networking.LoopbackURL()
+		if err != nil {
+			return err
+		}
+		m.baseURL = defaultUrl
+	}
+
+	endpoint := m.baseURL.String() + httpEndpointPath
+
+	srv := &http.Server{
+		Addr: m.baseURL.Host,
+	}
+	sessions := newSessionManager()
+	sessions.ended = m.subscriptions.removeSession
+	httpServer := server.NewStreamableHTTPServer(m.mcpServer,
+		server.WithEndpointPath(httpEndpointPath),
+		server.WithSessionIdManager(sessions),
+		server.WithHTTPContextFunc(func(ctx context.Context, _ *http.Request) context.Context {
+			return sessions.withClientSession(ctx, m.mcpServer)
+		}),
+		server.WithHeartbeatInterval(httpHeartbeatInterval),
+		server.WithStreamableHTTPServer(srv),
+	)
+
+	mux := http.NewServeMux()
+	mux.Handle(httpEndpointPath, middleware(m.subscriptions.middleware(httpServer, func(r *http.Request) string {
+		sessionID := r.Header.Get(httpSessionIdHeader)
+		if isTerminated, err := sessions.Validate(sessionID); err != nil || isTerminated {
+			return ""
+		}
+		return sessionID
+	}), m.authToken))
+	srv.Handler = mux
+
+	m.mutex.Lock()
+	m.httpServer = httpServer
+	m.mutex.Unlock()
+
+	m.logger.Info().Str("baseURL", endpoint).Msg("starting")
+	go m.markStartedWhenListening(endpoint)
+
+	err := srv.ListenAndServe()
+
+	if err != nil {
+		// expect http.ErrServerClosed when shutting down
+		if !errors.Is(err, http.ErrServerClosed) {
+			m.logger.Error().Err(err).Msg("Error starting MCP HTTP server")
+		}
+		return err
+	}
+	return nil
+}
+
+// markStartedWhenListening waits until the configured base URL accepts connections and flags the server as started
+func (m *McpLLMBinding) markStartedWhenListening(endpoint string) {
+	// sleep initially for a few milliseconds so we actually can start the server
+	time.Sleep(100 * time.Millisecond)
+	for !networking.IsPortInUse(m.baseURL) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	m.mutex.Lock()
+	m.logger.Info().Str("baseURL", endpoint).Msg("started")
+	m.started = true
+	m.mutex.Unlock()
+}
+
+func middleware(next http.Handler, authToken string) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if networking.IsValidLoopbackRequest(r) {
-			sseServer.ServeHTTP(w, r)
-		} else {
+		if !networking.IsValidLoopbackRequest(r) {
 			http.Error(w, "Forbidden: Access restricted to localhost origins", http.StatusForbidden)
+			return
+		}
+		if !isAuthorizedRequest(r, authToken) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "Unauthorized: missing or invalid bearer token", http.StatusUnauthorized)
+			return
 		}
+		next.ServeHTTP(w, r)
 	})
 }
 
@@ -197,12 +349,21 @@ func (m *McpLLMBinding) Shutdown(ctx context.Context) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
+	m.scanJobs.stop()
+
 	if m.sseServer != nil {
 		err := m.sseServer.Shutdown(ctx)
 		if err != nil {
 			m.logger.Error().Err(err).Msg("Error shutting down MCP SSE server")
 		}
 	}
+
+	if m.httpServer != nil {
+		err := m.httpServer.Shutdown(ctx)
+		if err != nil {
+			m.logger.Error().Err(err).Msg("Error shutting down MCP HTTP server")
+		}
+	}
 }
 
 func (m *McpLLMBinding) Started() bool {
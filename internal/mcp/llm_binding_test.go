@@ -16,16 +16,20 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/rs/zerolog"
 	"github.com/snyk/studio-mcp/internal/networking"
@@ -303,13 +307,25 @@ func TestShutdown(t *testing.T) {
 		// Verify the shutdown was attempted
 		assert.NotNil(t, binding.sseServer)
 	})
+
+	t.Run("handles shutdown with HTTP server", func(t *testing.T) {
+		binding := NewMcpLLMBinding()
+
+		mcpServer := server.NewMCPServer("test", "1.0.0")
+		binding.httpServer = server.NewStreamableHTTPServer(mcpServer)
+
+		ctx := t.Context()
+		binding.Shutdown(ctx)
+
+		assert.NotNil(t, binding.httpServer)
+	})
 }
 
 func TestMiddleware(t *testing.T) {
 	t.Run("allows valid localhost requests", func(t *testing.T) {
 		mcpServer := server.NewMCPServer("test", "1.0.0")
 		sseServer := server.NewSSEServer(mcpServer)
-		handler := middleware(sseServer)
+		handler := middleware(sseServer, "")
 
 		req := httptest.NewRequest(http.MethodGet, "/", nil)
 		req.Host = "localhost"
@@ -325,7 +341,7 @@ func TestMiddleware(t *testing.T) {
 	t.Run("blocks invalid external requests", func(t *testing.T) {
 		mcpServer := server.NewMCPServer("test", "1.0.0")
 		sseServer := server.NewSSEServer(mcpServer)
-		handler := middleware(sseServer)
+		handler := middleware(sseServer, "")
 
 		req := httptest.NewRequest(http.MethodGet, "/", nil)
 		req.Host = "example.com"
@@ -337,6 +353,68 @@ func TestMiddleware(t *testing.T) {
 		assert.Equal(t, http.StatusForbidden, rr.Code)
 		assert.Contains(t, rr.Body.String(), "Forbidden: Access restricted to localhost origins")
 	})
+
+	t.Run("requires bearer token when configured", func(t *testing.T) {
+		mcpServer := server.NewMCPServer("test", "1.0.0")
+		sseServer := server.NewSSEServer(mcpServer)
+		handler := middleware(sseServer, "secret")
+
+		tests := []struct {
+			name          string
+			authorization string
+			expected      int
+		}{
+			{name: "missing token", authorization: "", expected: http.StatusUnauthorized},
+			{name: "wrong token", authorization: "Bearer wrong", expected: http.StatusUnauthorized},
+			{name: "wrong scheme", authorization: "Basic secret", expected: http.StatusUnauthorized},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.Host = "localhost"
+				if tt.authorization != "" {
+					req.Header.Set("Authorization", tt.authorization)
+				}
+
+				rr := httptest.NewRecorder()
+				handler.ServeHTTP(rr, req)
+
+				assert.Equal(t, tt.expected, rr.Code)
+				assert.Equal(t, "Bearer", rr.Header().Get("WWW-Authenticate"))
+			})
+		}
+	})
+
+	t.Run("allows requests with valid bearer token", func(t *testing.T) {
+		mcpServer := server.NewMCPServer("test", "1.0.0")
+		httpServer := server.NewStreamableHTTPServer(mcpServer)
+		handler := middleware(httpServer, "secret")
+
+		req := httptest.NewRequest(http.MethodPost, httpEndpointPath, nil)
+		req.Host = "localhost"
+		req.Header.Set("Authorization", "Bearer secret")
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		assert.NotEqual(t, http.StatusUnauthorized, rr.Code)
+		assert.NotEqual(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("blocks invalid external requests to streamable HTTP server", func(t *testing.T) {
+		mcpServer := server.NewMCPServer("test", "1.0.0")
+		httpServer := server.NewStreamableHTTPServer(mcpServer)
+		handler := middleware(httpServer, "")
+
+		req := httptest.NewRequest(http.MethodPost, httpEndpointPath, nil)
+		req.Host = "localhost"
+		req.Header.Set("Origin", "http://evil.example.com")
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
 }
 
 func TestHandleStdioServer(t *testing.T) {
@@ -392,6 +470,88 @@ func TestHandleSseServer(t *testing.T) {
 	})
 }
 
+func TestHandleHttpServer(t *testing.T) {
+	baseURL, err := networking.LoopbackURL()
+	require.NoError(t, err)
+
+	binding := NewMcpLLMBinding(WithBaseURL(baseURL))
+	binding.mcpServer = server.NewMCPServer("test", "1.0.0")
+	binding.mcpServer.AddTool(mcp.NewTool("client_info"), func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		clientInfo := ClientInfoFromContext(ctx)
+		return mcp.NewToolResultText(clientInfo.Name + " " + clientInfo.Version), nil
+	})
+
+	go func() {
+		_ = binding.HandleHttpServer()
+	}()
+	t.Cleanup(func() {
+		binding.Shutdown(context.Background())
+	})
+	require.Eventually(t, binding.Started, 5*time.Second, 10*time.Millisecond)
+
+	endpoint := baseURL.String() + httpEndpointPath
+	post := func(t *testing.T, sessionID, body string) *http.Response {
+		t.Helper()
+		req, reqErr := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(body))
+		require.NoError(t, reqErr)
+		req.Header.Set("Content-Type", "application/json")
+		if sessionID != "" {
+			req.Header.Set("Mcp-Session-Id", sessionID)
+		}
+		resp, respErr := http.DefaultClient.Do(req)
+		require.NoError(t, respErr)
+		t.Cleanup(func() { _ = resp.Body.Close() })
+		return resp
+	}
+
+	initResp := post(t, "", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1.0.0"}}}`)
+	require.Equal(t, http.StatusOK, initResp.StatusCode)
+	sessionID := initResp.Header.Get("Mcp-Session-Id")
+	require.NotEmpty(t, sessionID)
+
+	t.Run("resumes a known session", func(t *testing.T) {
+		resp := post(t, sessionID, `{"jsonrpc":"2.0","id":2,"method":"ping"}`)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("keeps the client info of the session", func(t *testing.T) {
+		resp := post(t, sessionID, `{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"client_info"}}`)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		body, readErr := io.ReadAll(resp.Body)
+		require.NoError(t, readErr)
+		assert.Contains(t, string(body), "test 1.0.0")
+	})
+
+	t.Run("subscribes the session to a resource", func(t *testing.T) {
+		uri := scanResourceUri("abc")
+		resp := post(t, sessionID, `{"jsonrpc":"2.0","id":6,"method":"resources/subscribe","params":{"uri":"`+uri+`"}}`)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		body, readErr := io.ReadAll(resp.Body)
+		require.NoError(t, readErr)
+		assert.JSONEq(t, `{"jsonrpc":"2.0","id":6,"result":{}}`, string(body))
+		assert.Equal(t, []string{sessionID}, binding.subscriptions.subscribers(uri))
+	})
+
+	t.Run("rejects an unknown session", func(t *testing.T) {
+		resp := post(t, "snyk-mcp-"+uuid.New().String(), `{"jsonrpc":"2.0","id":3,"method":"ping"}`)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("rejects a terminated session", func(t *testing.T) {
+		req, reqErr := http.NewRequest(http.MethodDelete, endpoint, nil)
+		require.NoError(t, reqErr)
+		req.Header.Set("Mcp-Session-Id", sessionID)
+		deleteResp, respErr := http.DefaultClient.Do(req)
+		require.NoError(t, respErr)
+		_ = deleteResp.Body.Close()
+		require.Equal(t, http.StatusOK, deleteResp.StatusCode)
+
+		resp := post(t, sessionID, `{"jsonrpc":"2.0","id":4,"method":"ping"}`)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Empty(t, binding.subscriptions.subscribers(scanResourceUri("abc")), "subscriptions end with the session")
+	})
+}
+
 func TestStart(t *testing.T) {
 	t.Run("panics with nil invocation context", func(t *testing.T) {
 		binding := NewMcpLLMBinding()
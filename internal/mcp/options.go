@@ -42,3 +42,10 @@ func WithBaseURL(baseURL *url.URL) func(server *McpLLMBinding) {
 		server.baseURL = baseURL
 	}
 }
+
+// WithAuthToken sets the bearer token HTTP clients must present. An empty token disables the check.
+func WithAuthToken(authToken string) Option {
+	return func(server *McpLLMBinding) {
+		server.authToken = authToken
+	}
+}
@@ -0,0 +1,69 @@
+//go:build !windows
+
+/*
+ * © 2025 Snyk Limited
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/studio-mcp/shared"
+)
+
+func TestDefaultHandlerTimeout(t *testing.T) {
+	fixture := setupTestFixture(t)
+	fixture.invocationContext.GetConfiguration().Set(shared.ToolTimeoutParam, 1)
+	pidFile := filepath.Join(t.TempDir(), "child.pid")
+	// the child stands in for a build tool that the CLI spawned and that hangs
+	createMockSnykCliWithScript(t, fixture.snykCliPath, fmt.Sprintf(`#!/bin/sh
+sleep 60 &
+echo $! > '%s'
+wait
+`, pidFile))
+	tool := getToolWithName(t, fixture.tools, ToolName.ScaTest)
+	require.NotNil(t, tool)
+	handler := fixture.binding.defaultHandler(fixture.invocationContext, *tool)
+
+	startedAt := time.Now()
+	output := callToolWithArgs(t, handler, map[string]any{"path": t.TempDir()})
+
+	assert.Less(t, time.Since(startedAt), 10*time.Second)
+	var interruption toolInterruption
+	require.NoError(t, json.Unmarshal([]byte(output), &interruption))
+	assert.Equal(t, "timeout", interruption.Error.Code)
+	assert.Equal(t, 1, interruption.Error.TimeoutSeconds)
+
+	pidContent, err := os.ReadFile(pidFile)
+	require.NoError(t, err)
+	pid, err := strconv.Atoi(strings.TrimSpace(string(pidContent)))
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		// signal 0 only checks whether the process still exists
+		return syscall.Kill(pid, 0) != nil
+	}, 5*time.Second, 50*time.Millisecond, "child process should have been killed with the process group")
+}
@@ -0,0 +1,42 @@
+//go:build windows
+
+/*
+ * © 2025 Snyk Limited
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"os/exec"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// processWaitDelay bounds how long output is read after the process was killed
+const processWaitDelay = 5 * time.Second
+
+// configureProcessGroup starts the command in its own process group and kills the whole tree on cancellation
+func configureProcessGroup(command *exec.Cmd) {
+	command.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+	command.Cancel = func() error {
+		killErr := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(command.Process.Pid)).Run()
+		if killErr != nil {
+			return command.Process.Kill()
+		}
+		return nil
+	}
+	command.WaitDelay = processWaitDelay
+}
@@ -171,6 +171,8 @@ func TestIsToolInProfile(t *testing.T) {
 		{"snyk_logout", true, true, true},
 		{"snyk_trust", true, true, true},
 		{"snyk_send_feedback", true, true, true},
+		{"snyk_scan_status", true, true, true},
+		{"snyk_scan_result", true, true, true},
 
 		// Tools in full and experimental only
 		{"snyk_container_scan", false, true, true},
@@ -179,6 +181,13 @@ func TestIsToolInProfile(t *testing.T) {
 		{"snyk_aibom", false, true, true},
 		{"snyk_package_health_check", false, true, true},
 		{"snyk_breakability_check", false, true, true},
+		{"snyk_ignore", false, true, true},
+		{"snyk_apply_upgrade", false, true, true},
+		{"snyk_monitor", false, true, true},
+		{"snyk_container_monitor", false, true, true},
+		{"snyk_iac_monitor", false, true, true},
+		{"snyk_dependency_tree", false, true, true},
+		{"snyk_sbom_generate", false, true, true},
 
 		// Tools in experimental only
 		{"snyk_secret_scan", false, false, true},
@@ -0,0 +1,220 @@
+/*
+ * © 2025 Snyk Limited
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rs/zerolog"
+)
+
+const progressNotificationMethod = "notifications/progress"
+
+// progressHeartbeatInterval is how often progress is re-sent while the CLI gives no new output,
+// so clients with request timeouts that reset on progress don't give up on long scans
+const progressHeartbeatInterval = 10 * time.Second
+
+type progressReporterKey struct{}
+
+type progressWriterKey struct{}
+
+// progressPhase maps CLI output to a progress message. If the message contains a %s verb,
+// it is filled with the first submatch of the pattern.
+type progressPhase struct {
+	pattern *regexp.Regexp
+	message string
+}
+
+var progressPhases = []progressPhase{
+	{pattern: regexp.MustCompile(`(?i)(looking for supported|detect(ing|ed) .*(projects|manifests|files))`), message: "Discovering projects"},
+	{pattern: regexp.MustCompile(`(?i)testing\s+(\S+)`), message: "Testing %s"},
+	{pattern: regexp.MustCompile(`(?i)upload(ing)?\b`), message: "Uploading files for analysis"},
+	{pattern: regexp.MustCompile(`(?i)(analy[sz]ing|waiting for analysis)`), message: "Analyzing"},
+}
+
+// progressReporter sends notifications/progress for a single tool call.
+// A reporter without progress token is a no-op, as the client did not ask for progress.
+type progressReporter struct {
+	ctx       context.Context
+	logger    *zerolog.Logger
+	mcpServer *server.MCPServer
+	token     mcp.ProgressToken
+	interval  time.Duration
+
+	mutex    sync.Mutex
+	progress float64
+	message  string
+	pending  []byte
+}
+
+func newProgressReporter(ctx context.Context, logger *zerolog.Logger, mcpServer *server.MCPServer, request mcp.CallToolRequest) *progressReporter {
+	var token mcp.ProgressToken
+	if request.Params.Meta != nil {
+		token = request.Params.Meta.ProgressToken
+	}
+	return &progressReporter{
+		ctx:       ctx,
+		logger:    logger,
+		mcpServer: mcpServer,
+		token:     token,
+		interval:  progressHeartbeatInterval,
+	}
+}
+
+func contextWithProgressReporter(ctx context.Context, reporter *progressReporter) context.Context {
+	return context.WithValue(ctx, progressReporterKey{}, reporter)
+}
+
+func progressReporterFromContext(ctx context.Context) *progressReporter {
+	reporter, ok := ctx.Value(progressReporterKey{}).(*progressReporter)
+	if !ok {
+		return nil
+	}
+	return reporter
+}
+
+// contextWithProgressWriter makes CLI output go to the writer rather than to the progress reporter of the context
+func contextWithProgressWriter(ctx context.Context, writer io.Writer) context.Context {
+	return context.WithValue(ctx, progressWriterKey{}, writer)
+}
+
+// progressWriterFromContext returns the writer that turns CLI output into progress
+func progressWriterFromContext(ctx context.Context) io.Writer {
+	if writer, ok := ctx.Value(progressWriterKey{}).(io.Writer); ok {
+		return writer
+	}
+	return progressReporterFromContext(ctx).Writer()
+}
+
+func (p *progressReporter) enabled() bool {
+	return p != nil && p.token != nil && p.mcpServer != nil
+}
+
+// Report sends a progress notification with the given message
+func (p *progressReporter) Report(message string) {
+	if !p.enabled() {
+		return
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.message = message
+	p.send()
+}
+
+// must be called with the mutex held
+func (p *progressReporter) send() {
+	p.progress++
+	params := map[string]any{
+		"progressToken": p.token,
+		"progress":      p.progress,
+	}
+	if p.message != "" {
+		params["message"] = p.message
+	}
+	err := p.mcpServer.SendNotificationToClient(p.ctx, progressNotificationMethod, params)
+	if err != nil {
+		p.logger.Debug().Err(err).Msg("Failed to send progress notification")
+	}
+}
+
+// StartHeartbeat re-sends the last message periodically until the returned stop function is called
+func (p *progressReporter) StartHeartbeat() (stop func()) {
+	if !p.enabled() {
+		return func() {}
+	}
+	done := make(chan struct{})
+	var once sync.Once
+	go func() {
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-p.ctx.Done():
+				return
+			case <-ticker.C:
+				p.mutex.Lock()
+				p.send()
+				p.mutex.Unlock()
+			}
+		}
+	}()
+	return func() { once.Do(func() { close(done) }) }
+}
+
+// Writer returns a writer that turns CLI output lines into progress messages
+func (p *progressReporter) Writer() io.Writer {
+	if !p.enabled() {
+		return io.Discard
+	}
+	return progressLineWriter{reporter: p}
+}
+
+type progressLineWriter struct {
+	reporter *progressReporter
+}
+
+func (w progressLineWriter) Write(data []byte) (int, error) {
+	p := w.reporter
+	p.mutex.Lock()
+	p.pending = append(p.pending, data...)
+	var lines []string
+	for {
+		idx := bytes.IndexAny(p.pending, "\r\n")
+		if idx < 0 {
+			break
+		}
+		lines = append(lines, string(p.pending[:idx]))
+		p.pending = p.pending[idx+1:]
+	}
+	p.mutex.Unlock()
+
+	for _, line := range lines {
+		if message, ok := progressMessageForLine(line); ok {
+			p.Report(message)
+		}
+	}
+	return len(data), nil
+}
+
+func progressMessageForLine(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return "", false
+	}
+	for _, phase := range progressPhases {
+		match := phase.pattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		if strings.Contains(phase.message, "%s") && len(match) > 1 {
+			return fmt.Sprintf(phase.message, strings.TrimRight(match[1], ".:")), true
+		}
+		return phase.message, true
+	}
+	return "", false
+}
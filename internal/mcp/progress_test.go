@@ -0,0 +1,191 @@
+/*
+ * © 2025 Snyk Limited
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupProgressReporter(t *testing.T, token mcp.ProgressToken) (*progressReporter, *fakeClientSession) {
+	t.Helper()
+	logger := zerolog.New(io.Discard)
+	mcpServer := server.NewMCPServer("Snyk", "1.1.1")
+	session := &fakeClientSession{notifications: make(chan mcp.JSONRPCNotification, 100)}
+	ctx := mcpServer.WithContext(context.Background(), session)
+
+	request := mcp.CallToolRequest{}
+	if token != nil {
+		request.Params.Meta = &mcp.Meta{ProgressToken: token}
+	}
+	return newProgressReporter(ctx, &logger, mcpServer, request), session
+}
+
+func drainNotifications(session *fakeClientSession) []mcp.JSONRPCNotification {
+	var notifications []mcp.JSONRPCNotification
+	for {
+		select {
+		case notification := <-session.notifications:
+			notifications = append(notifications, notification)
+		default:
+			return notifications
+		}
+	}
+}
+
+func TestProgressMessageForLine(t *testing.T) {
+	testCases := []struct {
+		line            string
+		expectedMessage string
+		expectedOk      bool
+	}{
+		{line: "Looking for supported files...", expectedMessage: "Discovering projects", expectedOk: true},
+		{line: "Detected 3 projects", expectedMessage: "Discovering projects", expectedOk: true},
+		{line: "Testing package.json...", expectedMessage: "Testing package.json", expectedOk: true},
+		{line: "Uploading files", expectedMessage: "Uploading files for analysis", expectedOk: true},
+		{line: "Analyzing source code", expectedMessage: "Analyzing", expectedOk: true},
+		{line: "   ", expectedOk: false},
+		{line: "some unrelated debug output", expectedOk: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.line, func(t *testing.T) {
+			message, ok := progressMessageForLine(tc.line)
+			assert.Equal(t, tc.expectedOk, ok)
+			assert.Equal(t, tc.expectedMessage, message)
+		})
+	}
+}
+
+func TestProgressReporter(t *testing.T) {
+	t.Run("sends increasing progress with token and message", func(t *testing.T) {
+		reporter, session := setupProgressReporter(t, "token-1")
+
+		reporter.Report("first")
+		reporter.Report("second")
+
+		notifications := drainNotifications(session)
+		require.Len(t, notifications, 2)
+		assert.Equal(t, progressNotificationMethod, notifications[0].Method)
+		assert.Equal(t, "token-1", notifications[0].Params.AdditionalFields["progressToken"])
+		assert.Equal(t, "first", notifications[0].Params.AdditionalFields["message"])
+		assert.Less(t, notifications[0].Params.AdditionalFields["progress"], notifications[1].Params.AdditionalFields["progress"])
+	})
+
+	t.Run("does nothing without progress token", func(t *testing.T) {
+		reporter, session := setupProgressReporter(t, nil)
+
+		reporter.Report("ignored")
+		stop := reporter.StartHeartbeat()
+		stop()
+		_, _ = reporter.Writer().Write([]byte("Testing package.json\n"))
+
+		assert.Empty(t, drainNotifications(session))
+	})
+
+	t.Run("nil reporter is safe to use", func(t *testing.T) {
+		var reporter *progressReporter
+
+		reporter.Report("ignored")
+		reporter.StartHeartbeat()()
+		_, err := reporter.Writer().Write([]byte("Testing package.json\n"))
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("writer reports recognized lines across writes", func(t *testing.T) {
+		reporter, session := setupProgressReporter(t, 7)
+		writer := reporter.Writer()
+
+		_, _ = writer.Write([]byte("Testing pack"))
+		assert.Empty(t, drainNotifications(session))
+		_, _ = writer.Write([]byte("age.json\nunrelated\n"))
+
+		notifications := drainNotifications(session)
+		require.Len(t, notifications, 1)
+		assert.Equal(t, "Testing package.json", notifications[0].Params.AdditionalFields["message"])
+	})
+
+	t.Run("heartbeat re-sends last message until stopped", func(t *testing.T) {
+		reporter, session := setupProgressReporter(t, "token-2")
+		reporter.interval = 5 * time.Millisecond
+		reporter.Report("Running")
+
+		stop := reporter.StartHeartbeat()
+		assert.Eventually(t, func() bool { return len(session.notifications) >= 3 }, time.Second, time.Millisecond)
+		stop()
+		stop()
+
+		notifications := drainNotifications(session)
+		for _, notification := range notifications {
+			assert.Equal(t, "Running", notification.Params.AdditionalFields["message"])
+		}
+		time.Sleep(20 * time.Millisecond)
+		assert.LessOrEqual(t, len(session.notifications), 1)
+	})
+}
+
+func TestDefaultHandlerProgress(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("mock CLI script writes to stderr using sh")
+	}
+	fixture := setupTestFixture(t)
+	createMockSnykCliWithScript(t, fixture.snykCliPath, `#!/bin/sh
+echo 'Testing package.json...' >&2
+echo '{"ok": true}'
+exit 0
+`)
+	toolDef := getToolWithName(t, fixture.tools, ToolName.ScaTest)
+	require.NotNil(t, toolDef)
+	handler := fixture.binding.defaultHandler(fixture.invocationContext, *toolDef)
+
+	session := &fakeClientSession{notifications: make(chan mcp.JSONRPCNotification, 100)}
+	ctx := fixture.binding.mcpServer.WithContext(context.Background(), session)
+
+	requestObj := map[string]any{
+		"params": map[string]any{
+			"_meta":     map[string]any{"progressToken": "scan-1"},
+			"arguments": map[string]any{"path": t.TempDir()},
+		},
+	}
+	requestJSON, err := json.Marshal(requestObj)
+	require.NoError(t, err)
+	var request mcp.CallToolRequest
+	require.NoError(t, json.Unmarshal(requestJSON, &request))
+
+	result, err := handler(ctx, request)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	var messages []any
+	for _, notification := range drainNotifications(session) {
+		assert.Equal(t, "scan-1", notification.Params.AdditionalFields["progressToken"])
+		messages = append(messages, notification.Params.AdditionalFields["message"])
+	}
+	assert.Contains(t, messages, "Running "+ToolName.ScaTest)
+	assert.Contains(t, messages, "Testing package.json")
+	assert.Contains(t, messages, "Processing scan results")
+}
@@ -0,0 +1,191 @@
+/*
+ * © 2025 Snyk Limited
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/snyk/studio-mcp/internal/configure"
+)
+
+const (
+	promptArgPath     = "path"
+	promptArgSeverity = "severity"
+	promptArgPackage  = "package"
+)
+
+// PromptName defines all built-in prompt names.
+var PromptName = struct {
+	SecureChange      string
+	TriageSca         string
+	UpgradeDependency string
+}{
+	SecureChange:      "snyk_secure_change",
+	TriageSca:         "snyk_triage_sca_findings",
+	UpgradeDependency: "snyk_upgrade_vulnerable_dependency",
+}
+
+var promptSeverities = []string{"low", "medium", "high", "critical"}
+
+// promptParams are the validated arguments a prompt is rendered with
+type promptParams struct {
+	path     string
+	severity string
+	pkg      string
+}
+
+// securityPrompt describes a guided workflow. The rendered text always starts with the same
+// instructions as the rule files written by `configure`, so hosts without rule support get the same guidance.
+type securityPrompt struct {
+	name        string
+	description string
+	withPackage bool
+	render      func(params promptParams) string
+}
+
+var securityPrompts = []securityPrompt{
+	{
+		name:        PromptName.SecureChange,
+		description: "Scan and fix security issues introduced by the current change.",
+		render:      renderSecureChangePrompt,
+	},
+	{
+		name:        PromptName.TriageSca,
+		description: "Scan the open source dependencies and prioritize the findings.",
+		render:      renderTriageScaPrompt,
+	},
+	{
+		name:        PromptName.UpgradeDependency,
+		description: "Upgrade a vulnerable dependency to a fixed version and verify the fix.",
+		withPackage: true,
+		render:      renderUpgradeDependencyPrompt,
+	},
+}
+
+// addSnykPrompts registers the built-in security workflow prompts
+func (m *McpLLMBinding) addSnykPrompts() {
+	for _, prompt := range securityPrompts {
+		options := []mcp.PromptOption{
+			mcp.WithPromptDescription(prompt.description),
+			mcp.WithArgument(promptArgPath,
+				mcp.RequiredArgument(),
+				mcp.ArgumentDescription("Absolute path of the project or folder to work on."),
+			),
+			mcp.WithArgument(promptArgSeverity,
+				mcp.ArgumentDescription("Minimum severity to report. Accepted values: `low`, `medium`, `high`, `critical`."),
+			),
+		}
+		if prompt.withPackage {
+			options = append(options, mcp.WithArgument(promptArgPackage,
+				mcp.ArgumentDescription("Name of the dependency to upgrade. If omitted, the most severe fixable dependency is chosen."),
+			))
+		}
+		m.mcpServer.AddPrompt(mcp.NewPrompt(prompt.name, options...), promptHandler(prompt))
+	}
+}
+
+func promptHandler(prompt securityPrompt) server.PromptHandlerFunc {
+	return func(_ context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		args := request.Params.Arguments
+		params := promptParams{
+			path:     strings.TrimSpace(args[promptArgPath]),
+			severity: strings.ToLower(strings.TrimSpace(args[promptArgSeverity])),
+			pkg:      strings.TrimSpace(args[promptArgPackage]),
+		}
+		if params.path == "" {
+			return nil, fmt.Errorf("argument %q is required", promptArgPath)
+		}
+		if params.severity != "" && !slices.Contains(promptSeverities, params.severity) {
+			return nil, fmt.Errorf("invalid severity %q, accepted values: %s", params.severity, strings.Join(promptSeverities, ", "))
+		}
+
+		text := configure.SecurityRules() + "\n\n# Task\n\n" + prompt.render(params)
+		return mcp.NewGetPromptResult(prompt.description, []mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text)),
+		}), nil
+	}
+}
+
+// scanInstruction describes a tool call with path and, if given, severity threshold
+func scanInstruction(toolName string, path string, severity string) string {
+	instruction := fmt.Sprintf("Run the %s tool with `path` set to `%s`", toolName, path)
+	if severity != "" {
+		instruction += fmt.Sprintf(" and `severity_threshold` set to `%s`", severity)
+	}
+	return instruction
+}
+
+func renderSecureChangePrompt(params promptParams) string {
+	// Snyk Code has no critical severity, so high is the strictest threshold it accepts
+	codeSeverity := params.severity
+	if codeSeverity == "critical" {
+		codeSeverity = "high"
+	}
+	return fmt.Sprintf(`Secure the current change in %s.
+
+1. %s.
+2. If dependencies were added or changed, also %s.
+3. Fix the issues that were introduced or touched by the change, using the issue details returned by Snyk.
+4. Rescan and repeat until no new issues are reported.
+5. Summarize the fixed issues and list any remaining issues that could not be fixed, with the reason.`,
+		params.path,
+		scanInstruction(ToolName.CodeTest, params.path, codeSeverity),
+		lowerFirst(scanInstruction(ToolName.ScaTest, params.path, params.severity)),
+	)
+}
+
+func renderTriageScaPrompt(params promptParams) string {
+	return fmt.Sprintf(`Triage the open source dependency findings in %s.
+
+1. %s.
+2. Group the findings by package and version. For each package note the highest severity, whether it is a direct or transitive dependency, what introduced it, and the lowest version that fixes all of its issues.
+3. Prioritize packages with a fix available and a high severity, and direct dependencies over transitive ones.
+4. Present the result as a table ordered by priority, and recommend the next upgrade to make. Do not change any files.`,
+		params.path,
+		scanInstruction(ToolName.ScaTest, params.path, params.severity),
+	)
+}
+
+func renderUpgradeDependencyPrompt(params promptParams) string {
+	target := "the most severe vulnerable dependency that has a fix available"
+	if params.pkg != "" {
+		target = fmt.Sprintf("the dependency `%s`", params.pkg)
+	}
+	return fmt.Sprintf(`Upgrade %s in %s to a version without known vulnerabilities.
+
+1. %s and determine the installed version and the lowest version that fixes its issues.
+2. Run the %s tool for the current and target version, and prefer the smallest upgrade without breaking changes.
+3. Update the manifest and lock file. For a transitive dependency, upgrade the direct dependency that introduces it.
+4. Rescan to verify the vulnerabilities are fixed and no new ones were introduced.`,
+		target,
+		params.path,
+		scanInstruction(ToolName.ScaTest, params.path, params.severity),
+		ToolName.Breakability,
+	)
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
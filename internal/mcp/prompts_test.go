@@ -0,0 +1,149 @@
+/*
+ * © 2025 Snyk Limited
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/studio-mcp/internal/configure"
+)
+
+func setupPromptBinding(t *testing.T) *McpLLMBinding {
+	t.Helper()
+	binding := NewMcpLLMBinding()
+	binding.mcpServer = server.NewMCPServer("Snyk", "1.1.1", server.WithPromptCapabilities(true))
+	binding.addSnykPrompts()
+	return binding
+}
+
+func handlePromptMessage(t *testing.T, binding *McpLLMBinding, method string, params any) mcp.JSONRPCMessage {
+	t.Helper()
+	message, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+		"params":  params,
+	})
+	require.NoError(t, err)
+	return binding.mcpServer.HandleMessage(context.Background(), message)
+}
+
+func TestPromptsList(t *testing.T) {
+	binding := setupPromptBinding(t)
+
+	response := handlePromptMessage(t, binding, "prompts/list", map[string]any{})
+
+	rpcResponse, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok)
+	result, ok := rpcResponse.Result.(mcp.ListPromptsResult)
+	require.True(t, ok)
+
+	names := map[string]mcp.Prompt{}
+	for _, prompt := range result.Prompts {
+		names[prompt.Name] = prompt
+	}
+	require.Len(t, names, 3)
+	assert.Contains(t, names, PromptName.SecureChange)
+	assert.Contains(t, names, PromptName.TriageSca)
+	require.Contains(t, names, PromptName.UpgradeDependency)
+	assert.Len(t, names[PromptName.UpgradeDependency].Arguments, 3)
+	assert.True(t, names[PromptName.SecureChange].Arguments[0].Required)
+}
+
+func TestPromptsGet(t *testing.T) {
+	testCases := []struct {
+		name             string
+		prompt           string
+		arguments        map[string]string
+		expectedContains []string
+		expectedMissing  []string
+	}{
+		{
+			name:             "secure change with severity",
+			prompt:           PromptName.SecureChange,
+			arguments:        map[string]string{"path": "/repo", "severity": "high"},
+			expectedContains: []string{ToolName.CodeTest, ToolName.ScaTest, "`/repo`", "`severity_threshold` set to `high`"},
+		},
+		{
+			name:             "secure change caps code severity at high",
+			prompt:           PromptName.SecureChange,
+			arguments:        map[string]string{"path": "/repo", "severity": "critical"},
+			expectedContains: []string{"`severity_threshold` set to `high`", "`severity_threshold` set to `critical`"},
+		},
+		{
+			name:             "triage without severity",
+			prompt:           PromptName.TriageSca,
+			arguments:        map[string]string{"path": "/repo"},
+			expectedContains: []string{ToolName.ScaTest},
+			expectedMissing:  []string{"severity_threshold"},
+		},
+		{
+			name:             "upgrade named package",
+			prompt:           PromptName.UpgradeDependency,
+			arguments:        map[string]string{"path": "/repo", "package": "lodash"},
+			expectedContains: []string{"`lodash`", ToolName.Breakability},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			binding := setupPromptBinding(t)
+
+			response := handlePromptMessage(t, binding, "prompts/get", map[string]any{"name": tc.prompt, "arguments": tc.arguments})
+
+			rpcResponse, ok := response.(mcp.JSONRPCResponse)
+			require.True(t, ok, "expected a successful response, got %#v", response)
+			result, ok := rpcResponse.Result.(mcp.GetPromptResult)
+			require.True(t, ok)
+			require.Len(t, result.Messages, 1)
+			textContent, ok := result.Messages[0].Content.(mcp.TextContent)
+			require.True(t, ok)
+
+			assert.Contains(t, textContent.Text, configure.SecurityRules())
+			for _, expected := range tc.expectedContains {
+				assert.Contains(t, textContent.Text, expected)
+			}
+			for _, missing := range tc.expectedMissing {
+				assert.NotContains(t, textContent.Text, missing)
+			}
+		})
+	}
+
+	t.Run("missing path returns error", func(t *testing.T) {
+		binding := setupPromptBinding(t)
+
+		response := handlePromptMessage(t, binding, "prompts/get", map[string]any{"name": PromptName.TriageSca})
+
+		_, isError := response.(mcp.JSONRPCError)
+		assert.True(t, isError)
+	})
+
+	t.Run("invalid severity returns error", func(t *testing.T) {
+		binding := setupPromptBinding(t)
+
+		response := handlePromptMessage(t, binding, "prompts/get", map[string]any{"name": PromptName.TriageSca, "arguments": map[string]string{"path": "/repo", "severity": "urgent"}})
+
+		_, isError := response.(mcp.JSONRPCError)
+		assert.True(t, isError)
+	})
+}
@@ -0,0 +1,134 @@
+/*
+ * © 2025 Snyk Limited
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	resourcesSubscribeMethod   = "resources/subscribe"
+	resourcesUnsubscribeMethod = "resources/unsubscribe"
+)
+
+// resourceSubscriptions tracks the resources each session subscribed to, so that resources/updated
+// is only sent to the sessions that asked for it.
+// mcp-go has no handlers for resources/subscribe and resources/unsubscribe and answers them with
+// "method not found". The transports therefore pass incoming messages through handle, which records
+// the subscription and hands the request on as a ping with the same ID. Its empty result is the
+// response the client expects.
+type resourceSubscriptions struct {
+	mutex    sync.Mutex
+	sessions map[string]map[string]bool
+}
+
+func newResourceSubscriptions() *resourceSubscriptions {
+	return &resourceSubscriptions{
+		sessions: make(map[string]map[string]bool),
+	}
+}
+
+// handle records a resources/subscribe or resources/unsubscribe request of the session and returns
+// the message to pass on to the MCP server
+func (s *resourceSubscriptions) handle(sessionID string, message []byte) []byte {
+	var request struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+		Params struct {
+			URI string `json:"uri"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(message, &request); err != nil || len(request.ID) == 0 || string(request.ID) == "null" || request.Params.URI == "" || sessionID == "" {
+		return message
+	}
+
+	switch request.Method {
+	case resourcesSubscribeMethod:
+		s.subscribe(sessionID, request.Params.URI)
+	case resourcesUnsubscribeMethod:
+		s.unsubscribe(sessionID, request.Params.URI)
+	default:
+		return message
+	}
+	return []byte(fmt.Sprintf(`{"jsonrpc":%q,"id":%s,"method":%q}`, mcp.JSONRPC_VERSION, request.ID, mcp.MethodPing))
+}
+
+func (s *resourceSubscriptions) subscribe(sessionID string, uri string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	uris, ok := s.sessions[sessionID]
+	if !ok {
+		uris = make(map[string]bool)
+		s.sessions[sessionID] = uris
+	}
+	uris[uri] = true
+}
+
+func (s *resourceSubscriptions) unsubscribe(sessionID string, uri string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.sessions[sessionID], uri)
+	if len(s.sessions[sessionID]) == 0 {
+		delete(s.sessions, sessionID)
+	}
+}
+
+// removeSession drops all subscriptions of a session that ended
+func (s *resourceSubscriptions) removeSession(sessionID string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.sessions, sessionID)
+}
+
+// subscribers returns the IDs of the sessions subscribed to the resource
+func (s *resourceSubscriptions) subscribers(uri string) []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var sessionIDs []string
+	for sessionID, uris := range s.sessions {
+		if uris[uri] {
+			sessionIDs = append(sessionIDs, sessionID)
+		}
+	}
+	sort.Strings(sessionIDs)
+	return sessionIDs
+}
+
+// middleware passes the messages posted to an HTTP transport through handle
+func (s *resourceSubscriptions) middleware(next http.Handler, sessionID func(r *http.Request) string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.Body != nil {
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, "Bad Request: failed to read request body", http.StatusBadRequest)
+				return
+			}
+			body = s.handle(sessionID(r), body)
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			r.ContentLength = int64(len(body))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
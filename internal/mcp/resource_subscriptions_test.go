@@ -0,0 +1,108 @@
+/*
+ * © 2025 Snyk Limited
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceSubscriptions(t *testing.T) {
+	uri := scanResourceUri("abc")
+
+	t.Run("subscribe is recorded and answered as ping", func(t *testing.T) {
+		subscriptions := newResourceSubscriptions()
+
+		message := subscriptions.handle("session-1", []byte(`{"jsonrpc":"2.0","id":"req-1","method":"resources/subscribe","params":{"uri":"`+uri+`"}}`))
+
+		assert.JSONEq(t, `{"jsonrpc":"2.0","id":"req-1","method":"ping"}`, string(message))
+		assert.Equal(t, []string{"session-1"}, subscriptions.subscribers(uri))
+		assert.Empty(t, subscriptions.subscribers(scanResourceUri("other")))
+	})
+
+	t.Run("unsubscribe removes the subscription", func(t *testing.T) {
+		subscriptions := newResourceSubscriptions()
+		subscriptions.subscribe("session-1", uri)
+		subscriptions.subscribe("session-2", uri)
+
+		message := subscriptions.handle("session-1", []byte(`{"jsonrpc":"2.0","id":2,"method":"resources/unsubscribe","params":{"uri":"`+uri+`"}}`))
+
+		assert.JSONEq(t, `{"jsonrpc":"2.0","id":2,"method":"ping"}`, string(message))
+		assert.Equal(t, []string{"session-2"}, subscriptions.subscribers(uri))
+	})
+
+	t.Run("ended session is removed", func(t *testing.T) {
+		subscriptions := newResourceSubscriptions()
+		subscriptions.subscribe("session-1", uri)
+
+		subscriptions.removeSession("session-1")
+
+		assert.Empty(t, subscriptions.subscribers(uri))
+	})
+
+	t.Run("other messages are passed on unchanged", func(t *testing.T) {
+		subscriptions := newResourceSubscriptions()
+		messages := map[string]string{
+			"other method":       `{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"` + uri + `"}}`,
+			"notification":       `{"jsonrpc":"2.0","method":"resources/subscribe","params":{"uri":"` + uri + `"}}`,
+			"missing uri":        `{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{}}`,
+			"invalid json":       `{"jsonrpc":`,
+			"subscribe with nil": `{"jsonrpc":"2.0","id":null,"method":"resources/subscribe","params":{"uri":"` + uri + `"}}`,
+		}
+		for name, message := range messages {
+			assert.Equal(t, message, string(subscriptions.handle("session-1", []byte(message))), name)
+		}
+		assert.Equal(t, `{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"`+uri+`"}}`,
+			string(subscriptions.handle("", []byte(`{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"`+uri+`"}}`))), "without session")
+		assert.Empty(t, subscriptions.subscribers(uri))
+	})
+}
+
+func TestStdioResourceSubscription(t *testing.T) {
+	mcpServer := server.NewMCPServer("Snyk", "1.1.1", server.WithResourceCapabilities(true, true))
+	subscriptions := newResourceSubscriptions()
+	stdinReader, stdin := io.Pipe()
+	stdoutReader, stdout := io.Pipe()
+	go func() {
+		_ = serveStdio(context.Background(), mcpServer, subscriptions, stdinReader, stdout)
+	}()
+	t.Cleanup(func() { _ = stdin.Close() })
+	responses := make(chan string, 1)
+	go func() {
+		line, _ := bufio.NewReader(stdoutReader).ReadString('\n')
+		responses <- line
+	}()
+
+	uri := scanResourceUri("abc")
+	_, err := io.WriteString(stdin, `{"jsonrpc":"2.0","id":3,"method":"resources/subscribe","params":{"uri":"`+uri+`"}}`+"\n")
+	require.NoError(t, err)
+
+	select {
+	case line := <-responses:
+		assert.JSONEq(t, `{"jsonrpc":"2.0","id":3,"result":{}}`, line)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no response to resources/subscribe")
+	}
+	assert.Equal(t, []string{"stdio"}, subscriptions.subscribers(uri))
+}
@@ -0,0 +1,311 @@
+/*
+ * © 2025 Snyk Limited
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/adrg/xdg"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+)
+
+const (
+	// resultCacheDir is the directory below the XDG cache home that holds cached scan results
+	resultCacheDir = "snyk/snyk-mcp/scan-results"
+	// resultCacheTTL bounds how long a result is reused. Unchanged inputs can still get new
+	// findings as the vulnerability database and rules evolve.
+	resultCacheTTL = time.Hour
+)
+
+// scaInputFiles are the manifests, lockfiles and policies that determine the result of an SCA scan
+var scaInputFiles = []string{
+	".snyk",
+	"package.json", "package-lock.json", "npm-shrinkwrap.json", "yarn.lock", "pnpm-lock.yaml",
+	"pom.xml", "build.gradle", "build.gradle.kts", "settings.gradle", "settings.gradle.kts", "gradle.lockfile", "build.sbt",
+	"requirements.txt", "setup.py", "setup.cfg", "Pipfile", "Pipfile.lock", "pyproject.toml", "poetry.lock", "uv.lock",
+	"go.mod", "go.sum", "Gopkg.lock", "vendor.json",
+	"Gemfile", "Gemfile.lock",
+	"composer.json", "composer.lock",
+	"packages.config", "project.assets.json", "paket.dependencies", "paket.lock", "Directory.Packages.props",
+	"Cargo.toml", "Cargo.lock",
+	"Package.swift", "Package.resolved", "Podfile", "Podfile.lock",
+	"mix.exs", "mix.lock",
+	"pubspec.yaml", "pubspec.lock",
+	"conanfile.txt", "conanfile.py",
+}
+
+// scaInputExtensions are manifest file extensions that don't have a fixed name
+var scaInputExtensions = []string{".csproj", ".fsproj", ".vbproj", ".sln"}
+
+// nodeModulesStateFiles are written to node_modules by npm, yarn and pnpm on every install. Without a lockfile,
+// the installed packages determine the result of an SCA scan.
+var nodeModulesStateFiles = []string{".package-lock.json", ".yarn-integrity", ".modules.yaml"}
+
+// codeIgnoreFiles are the ignore files honoured by Snyk Code in every directory
+var codeIgnoreFiles = []string{".gitignore", ".dcignore"}
+
+// inputHasher computes a digest of the files that determine a tool's result
+type inputHasher func(path string) (string, error)
+
+// resultCacheHashers maps the cached tools to their input hashers
+var resultCacheHashers = map[string]inputHasher{
+	ToolName.ScaTest:        hashScaInputs,
+	ToolName.CodeTest:       hashCodeInputs,
+	ToolName.DependencyTree: hashScaInputs,
+}
+
+type cachedResult struct {
+	CreatedAt time.Time `json:"createdAt"`
+	Output    string    `json:"output"`
+}
+
+// resultCache stores CLI output on disk, addressed by tool, arguments and a hash of the scanned inputs
+type resultCache struct {
+	dir string
+	ttl time.Duration
+	now func() time.Time
+}
+
+func newResultCache(dir string) *resultCache {
+	return &resultCache{dir: dir, ttl: resultCacheTTL, now: time.Now}
+}
+
+// newDefaultResultCache creates the cache below the XDG cache home
+func newDefaultResultCache() (*resultCache, error) {
+	dir, err := xdg.CacheFile(resultCacheDir)
+	if err != nil {
+		return nil, err
+	}
+	return newResultCache(dir), nil
+}
+
+// key computes the cache key, or returns false if the tool isn't cacheable
+func (c *resultCache) key(toolName string, workingDir string, args []string, org string) (string, bool, error) {
+	hasher, ok := resultCacheHashers[toolName]
+	if c == nil || !ok || workingDir == "" {
+		return "", false, nil
+	}
+	inputHash, err := hasher(workingDir)
+	if err != nil {
+		return "", false, err
+	}
+
+	sortedArgs := slices.Clone(args)
+	slices.Sort(sortedArgs)
+	h := sha256.New()
+	for _, part := range append([]string{toolName, workingDir, org, inputHash}, sortedArgs...) {
+		_, _ = io.WriteString(h, part)
+		_, _ = h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)), true, nil
+}
+
+func (c *resultCache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// get returns the cached output if present and not expired
+func (c *resultCache) get(key string) (string, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return "", false
+	}
+	var cached cachedResult
+	if err = json.Unmarshal(data, &cached); err != nil || c.now().Sub(cached.CreatedAt) > c.ttl {
+		_ = os.Remove(c.path(key))
+		return "", false
+	}
+	return cached.Output, true
+}
+
+// put stores the output and removes expired entries
+func (c *resultCache) put(key string, output string) error {
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return err
+	}
+	c.prune()
+
+	data, err := json.Marshal(cachedResult{CreatedAt: c.now().UTC(), Output: output})
+	if err != nil {
+		return err
+	}
+	// write to a temp file first, so concurrent readers never see a partial entry
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if err = errors.Join(writeErr, closeErr); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path(key))
+}
+
+func (c *resultCache) prune() {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		info, infoErr := entry.Info()
+		if infoErr == nil && c.now().Sub(info.ModTime()) > c.ttl {
+			_ = os.Remove(filepath.Join(c.dir, entry.Name()))
+		}
+	}
+}
+
+func isScaInputFile(name string) bool {
+	return slices.Contains(scaInputFiles, name) ||
+		slices.Contains(scaInputExtensions, strings.ToLower(filepath.Ext(name))) ||
+		(strings.HasPrefix(name, "requirements") && strings.HasSuffix(name, ".txt"))
+}
+
+// hashScaInputs hashes all manifests, lockfiles and policies below the path, and the install state of node_modules
+func hashScaInputs(path string) (string, error) {
+	return hashFiles(path, func(relPath []string, entry fs.DirEntry) (include bool, skipDir bool) {
+		inNodeModules := len(relPath) > 1 && relPath[len(relPath)-2] == "node_modules"
+		if entry.IsDir() {
+			return false, entry.Name() == ".git" || inNodeModules
+		}
+		if inNodeModules {
+			return slices.Contains(nodeModulesStateFiles, entry.Name()), false
+		}
+		return isScaInputFile(entry.Name()), false
+	}, hashFileContent)
+}
+
+// hashCodeInputs hashes the source tree below the path, leaving out files ignored like Snyk Code does.
+// Source trees can be large, so files are hashed by size and modification time instead of content.
+func hashCodeInputs(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	var patterns []gitignore.Pattern
+	if info.IsDir() {
+		patterns = readCodeIgnorePatterns(path, nil)
+	}
+	matcher := gitignore.NewMatcher(patterns)
+
+	return hashFiles(path, func(relPath []string, entry fs.DirEntry) (include bool, skipDir bool) {
+		if entry.IsDir() {
+			if entry.Name() == ".git" || matcher.Match(relPath, true) {
+				return false, true
+			}
+			if dirPatterns := readCodeIgnorePatterns(path, relPath); len(dirPatterns) > 0 {
+				patterns = append(patterns, dirPatterns...)
+				matcher = gitignore.NewMatcher(patterns)
+			}
+			return false, false
+		}
+		return !matcher.Match(relPath, false), false
+	}, hashFileStat)
+}
+
+// readCodeIgnorePatterns reads the ignore files of the directory below root. Patterns only apply within it.
+func readCodeIgnorePatterns(root string, dir []string) []gitignore.Pattern {
+	var patterns []gitignore.Pattern
+	for _, name := range codeIgnoreFiles {
+		content, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(strings.Join(append(slices.Clone(dir), name), "/"))))
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(string(content), "\n") {
+			line = strings.TrimRight(line, "\r")
+			if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			patterns = append(patterns, gitignore.ParsePattern(line, dir))
+		}
+	}
+	return patterns
+}
+
+// fileHasher adds a file, identified by its slash-separated relative name, to the hash
+type fileHasher func(h hash.Hash, name string, path string) error
+
+// hashFiles walks the path in lexical order and hashes every included file. If the path is a file, only
+// that file is hashed.
+func hashFiles(root string, filter func(relPath []string, entry fs.DirEntry) (include bool, skipDir bool), hashFile fileHasher) (string, error) {
+	h := sha256.New()
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if path == root && !entry.IsDir() {
+			return hashFile(h, entry.Name(), path)
+		}
+		if path == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		relPath := strings.Split(filepath.ToSlash(rel), "/")
+		include, skipDir := filter(relPath, entry)
+		if skipDir {
+			return filepath.SkipDir
+		}
+		if !include || !entry.Type().IsRegular() {
+			return nil
+		}
+		return hashFile(h, filepath.ToSlash(rel), path)
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to hash scan inputs: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func hashFileContent(h hash.Hash, name string, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, _ = io.WriteString(h, name)
+	_, _ = h.Write([]byte{0})
+	if _, err = io.Copy(h, file); err != nil {
+		return err
+	}
+	_, _ = h.Write([]byte{0})
+	return nil
+}
+
+func hashFileStat(h hash.Hash, name string, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(h, "%s\x00%d\x00%d\x00", name, info.Size(), info.ModTime().UnixNano())
+	return nil
+}
@@ -0,0 +1,199 @@
+/*
+ * © 2025 Snyk Limited
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package mcp
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestFile(t *testing.T, path string, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestHashScaInputs(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "package.json"), `{"name":"app"}`)
+	writeTestFile(t, filepath.Join(dir, "src", "index.js"), "console.log(1)")
+	initial, err := hashScaInputs(dir)
+	require.NoError(t, err)
+
+	t.Run("source changes don't affect the hash", func(t *testing.T) {
+		writeTestFile(t, filepath.Join(dir, "src", "index.js"), "console.log(2)")
+		writeTestFile(t, filepath.Join(dir, "node_modules", "dep", "package.json"), `{"name":"dep"}`)
+
+		current, err := hashScaInputs(dir)
+		require.NoError(t, err)
+		assert.Equal(t, initial, current)
+	})
+
+	t.Run("installed dependencies affect the hash", func(t *testing.T) {
+		writeTestFile(t, filepath.Join(dir, "node_modules", ".package-lock.json"), `{"packages":{}}`)
+
+		current, err := hashScaInputs(dir)
+		require.NoError(t, err)
+		assert.NotEqual(t, initial, current)
+	})
+
+	t.Run("manifest changes affect the hash", func(t *testing.T) {
+		writeTestFile(t, filepath.Join(dir, "services", "api", "requirements-dev.txt"), "flask==2.0.0")
+
+		current, err := hashScaInputs(dir)
+		require.NoError(t, err)
+		assert.NotEqual(t, initial, current)
+	})
+}
+
+func TestHashCodeInputs(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, ".gitignore"), "build/\n*.log\n")
+	writeTestFile(t, filepath.Join(dir, ".dcignore"), "vendor/\n")
+	writeTestFile(t, filepath.Join(dir, "web", ".gitignore"), "dist/\n")
+	writeTestFile(t, filepath.Join(dir, "main.go"), "package main")
+	initial, err := hashCodeInputs(dir)
+	require.NoError(t, err)
+
+	t.Run("ignored files don't affect the hash", func(t *testing.T) {
+		writeTestFile(t, filepath.Join(dir, "build", "out.js"), "generated")
+		writeTestFile(t, filepath.Join(dir, "debug.log"), "log")
+		writeTestFile(t, filepath.Join(dir, "vendor", "lib.go"), "package lib")
+		writeTestFile(t, filepath.Join(dir, "web", "dist", "app.js"), "bundle")
+
+		current, err := hashCodeInputs(dir)
+		require.NoError(t, err)
+		assert.Equal(t, initial, current)
+	})
+
+	t.Run("source changes affect the hash", func(t *testing.T) {
+		writeTestFile(t, filepath.Join(dir, "main.go"), "package main\n\nfunc main() {}")
+
+		current, err := hashCodeInputs(dir)
+		require.NoError(t, err)
+		assert.NotEqual(t, initial, current)
+	})
+
+	t.Run("single file is hashed", func(t *testing.T) {
+		first, err := hashCodeInputs(filepath.Join(dir, "main.go"))
+		require.NoError(t, err)
+		writeTestFile(t, filepath.Join(dir, "main.go"), "package changed")
+		second, err := hashCodeInputs(filepath.Join(dir, "main.go"))
+		require.NoError(t, err)
+
+		assert.NotEqual(t, first, second)
+	})
+}
+
+func TestResultCache(t *testing.T) {
+	workDir := t.TempDir()
+	writeTestFile(t, filepath.Join(workDir, "package.json"), `{}`)
+
+	t.Run("stores and returns output", func(t *testing.T) {
+		cache := newResultCache(t.TempDir())
+		key, cacheable, err := cache.key(ToolName.ScaTest, workDir, []string{"snyk", "test", "--json"}, "org")
+		require.NoError(t, err)
+		require.True(t, cacheable)
+
+		_, ok := cache.get(key)
+		assert.False(t, ok)
+		require.NoError(t, cache.put(key, "output"))
+		output, ok := cache.get(key)
+		assert.True(t, ok)
+		assert.Equal(t, "output", output)
+	})
+
+	t.Run("key depends on args and org but not flag order", func(t *testing.T) {
+		cache := newResultCache(t.TempDir())
+		key := func(args []string, org string) string {
+			k, _, err := cache.key(ToolName.ScaTest, workDir, args, org)
+			require.NoError(t, err)
+			return k
+		}
+
+		assert.Equal(t, key([]string{"--json", "--dev"}, "org"), key([]string{"--dev", "--json"}, "org"))
+		assert.NotEqual(t, key([]string{"--json"}, "org"), key([]string{"--json", "--dev"}, "org"))
+		assert.NotEqual(t, key([]string{"--json"}, "org"), key([]string{"--json"}, "other-org"))
+	})
+
+	t.Run("expired entries are not returned", func(t *testing.T) {
+		cache := newResultCache(t.TempDir())
+		now := time.Now()
+		cache.now = func() time.Time { return now }
+		require.NoError(t, cache.put("key", "output"))
+
+		now = now.Add(resultCacheTTL + time.Minute)
+		_, ok := cache.get("key")
+		assert.False(t, ok)
+	})
+
+	t.Run("tools without input hasher are not cacheable", func(t *testing.T) {
+		cache := newResultCache(t.TempDir())
+		_, cacheable, err := cache.key(ToolName.Version, workDir, nil, "")
+		require.NoError(t, err)
+		assert.False(t, cacheable)
+	})
+
+	t.Run("nil cache is not cacheable", func(t *testing.T) {
+		var cache *resultCache
+		_, cacheable, err := cache.key(ToolName.ScaTest, workDir, nil, "")
+		require.NoError(t, err)
+		assert.False(t, cacheable)
+	})
+}
+
+func TestDefaultHandlerUsesResultCache(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("mock CLI script uses sh")
+	}
+	fixture := setupTestFixture(t)
+	fixture.binding.resultCache = newResultCache(t.TempDir())
+	counterFile := filepath.Join(t.TempDir(), "invocations")
+	createMockSnykCliWithScript(t, fixture.snykCliPath, fmt.Sprintf(`#!/bin/sh
+echo run >> '%s'
+echo '{"ok": true}'
+exit 0
+`, counterFile))
+	tool := getToolWithName(t, fixture.tools, ToolName.ScaTest)
+	require.NotNil(t, tool)
+	handler := fixture.binding.defaultHandler(fixture.invocationContext, *tool)
+	path := t.TempDir()
+	writeTestFile(t, filepath.Join(path, "package.json"), `{"dependencies":{}}`)
+	invocations := func() int {
+		content, err := os.ReadFile(counterFile)
+		require.NoError(t, err)
+		return strings.Count(string(content), "run")
+	}
+
+	callToolWithArgs(t, handler, map[string]any{"path": path})
+	callToolWithArgs(t, handler, map[string]any{"path": path})
+	assert.Equal(t, 1, invocations(), "second scan should be served from cache")
+
+	callToolWithArgs(t, handler, map[string]any{"path": path, "no_cache": true})
+	assert.Equal(t, 2, invocations(), "no_cache should bypass the cache")
+
+	writeTestFile(t, filepath.Join(path, "package.json"), `{"dependencies":{"lodash":"4.17.0"}}`)
+	callToolWithArgs(t, handler, map[string]any{"path": path})
+	assert.Equal(t, 3, invocations(), "manifest change should invalidate the cache")
+}
@@ -0,0 +1,156 @@
+/*
+ * © 2025 Snyk Limited
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/snyk/go-application-framework/pkg/workflow"
+
+	"github.com/snyk/studio-mcp/internal/authentication"
+	"github.com/snyk/studio-mcp/internal/sbom"
+)
+
+// SbomGenerateResult is the response of snyk_sbom_generate. The document itself is only written to the file.
+type SbomGenerateResult struct {
+	File   string `json:"file"`
+	Format string `json:"format"`
+	// ComponentCount is the number of top-level components, or packages in SPDX documents
+	ComponentCount int `json:"componentCount"`
+}
+
+// sbomDocumentPath returns the file to write the SBOM to. With an output directory, the file is written there,
+// otherwise into the scanned directory, which the output file may not leave. The output file must have the
+// extension of the format, so that only SBOMs are overwritten and not e.g. manifests of the project.
+func sbomDocumentPath(outputDir string, workingDir string, outputFile string, format string) (string, error) {
+	extension := sbom.FileExtension(format)
+	if outputFile == "" {
+		outputFile = fmt.Sprintf("%s.%s", filepath.Base(workingDir), extension)
+	}
+	if !strings.HasSuffix(strings.ToLower(outputFile), "."+extension) {
+		return "", fmt.Errorf("output file '%s' must have the extension .%s of the %s format", outputFile, extension, format)
+	}
+	if outputDir != "" {
+		return filepath.Join(outputDir, filepath.Base(outputFile)), nil
+	}
+
+	path := outputFile
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(workingDir, path)
+	}
+	rel, err := filepath.Rel(workingDir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("output file '%s' is outside of '%s'", outputFile, workingDir)
+	}
+	return filepath.Clean(path), nil
+}
+
+// snykSbomGenerateHandler generates an SBOM of the project with the CLI and writes it into the project, or into
+// the output directory of the server
+func (m *McpLLMBinding) snykSbomGenerateHandler(invocationCtx workflow.InvocationContext, toolDef SnykMcpToolsDefinition) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		logger := m.logger.With().Str("method", toolDef.Name).Logger()
+		logger.Debug().Str("toolName", toolDef.Name).Msg("Received call for tool")
+
+		requestArgs := request.GetArguments()
+		format := strings.ToLower(strings.TrimSpace(getOptionalStringArg(requestArgs, "format")))
+		if format == "" {
+			format = sbom.DefaultFormat
+		}
+		if !slices.Contains(sbom.Formats, format) {
+			return mcp.NewToolResultText(fmt.Sprintf("Error: unsupported SBOM format %q, use one of %s", format, strings.Join(sbom.Formats, ", "))), nil
+		}
+
+		params, workingDir, err := prepareCmdArgsForTool(m.logger, toolDef, requestArgs)
+		if err != nil {
+			return nil, err
+		}
+		if workingDir == "" {
+			return mcp.NewToolResultText("Error: argument 'path' is required"), nil
+		}
+		params["format"] = convertedToolParameter{
+			SnykMcpToolParameter: SnykMcpToolParameter{Name: "format", Type: "string"},
+			value:                format,
+		}
+		// the document is written by the server, not the CLI
+		delete(params, "output-file")
+
+		if trustErr := m.folderTrustError(invocationCtx, toolDef, workingDir); trustErr != "" {
+			logger.Error().Msg(trustErr)
+			return mcp.NewToolResultText(trustErr), nil
+		}
+
+		user, whoAmiErr := authentication.CallWhoAmI(&logger, invocationCtx.GetEngine())
+		if whoAmiErr != nil || user == nil {
+			return mcp.NewToolResultText("User not authenticated. Please run 'snyk_auth' first"), nil
+		}
+
+		documentPath, err := sbomDocumentPath(outputDirPath(invocationCtx, workingDir), workingDir, strings.TrimSpace(getOptionalStringArg(requestArgs, "output_file")), format)
+		if err != nil {
+			return mcp.NewToolResultText(fmt.Sprintf("Error: %s", err.Error())), nil
+		}
+
+		timeout := toolTimeout(toolDef, invocationCtx.GetConfiguration())
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeoutCause(ctx, timeout, errToolTimeout)
+			defer cancel()
+		}
+		startedAt := time.Now()
+
+		output, err := m.runSnyk(ctx, invocationCtx, workingDir, buildCommand(m.cliPath, toolDef.Command, params))
+		if ctx.Err() != nil {
+			logger.Warn().Err(context.Cause(ctx)).Str("toolName", toolDef.Name).Str("workingDir", workingDir).Msg("Tool run interrupted")
+			return interruptedToolResult(ctx, toolDef, workingDir, timeout, time.Since(startedAt)), nil
+		}
+		if err != nil {
+			if output == "" {
+				return mcp.NewToolResultText(fmt.Sprintf("Error: %s", err.Error())), nil
+			}
+			return mcp.NewToolResultText(fmt.Sprintf("Error: %s", output)), nil
+		}
+
+		componentCount, err := sbom.CountComponents(format, []byte(output))
+		if err != nil {
+			return mcp.NewToolResultText(fmt.Sprintf("Error: the CLI output is %s: %s", err.Error(), output)), nil
+		}
+
+		if err = os.MkdirAll(filepath.Dir(documentPath), 0755); err == nil {
+			err = os.WriteFile(documentPath, []byte(output), 0644)
+		}
+		if err != nil {
+			logger.Error().Err(err).Str("file", documentPath).Msg("Failed to write SBOM")
+			return mcp.NewToolResultText(fmt.Sprintf("Error: failed to write %s: %s", documentPath, err.Error())), nil
+		}
+		logger.Info().Str("file", documentPath).Str("format", format).Int("components", componentCount).Msg("Generated SBOM")
+
+		resultJSON, err := json.Marshal(SbomGenerateResult{File: documentPath, Format: format, ComponentCount: componentCount})
+		if err != nil {
+			return nil, err
+		}
+		return mcp.NewToolResultText(string(resultJSON)), nil
+	}
+}
@@ -0,0 +1,132 @@
+/*
+ * © 2025 Snyk Limited
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const cycloneDXDocument = `{"bomFormat":"CycloneDX","specVersion":"1.6","components":[{"name":"express"},{"name":"qs"}]}`
+
+func TestSbomDocumentPath(t *testing.T) {
+	projectDir := filepath.Join(t.TempDir(), "service")
+	outputDir := t.TempDir()
+
+	testCases := []struct {
+		name       string
+		outputDir  string
+		outputFile string
+		format     string
+		expected   string
+	}{
+		{name: "default file in the project", format: "cyclonedx1.6+json", expected: filepath.Join(projectDir, "service.cdx.json")},
+		{name: "default SPDX file", format: "spdx2.3+json", expected: filepath.Join(projectDir, "service.spdx.json")},
+		{name: "relative output file", outputFile: filepath.Join("sbom", "bom.cdx.xml"), format: "cyclonedx1.5+xml", expected: filepath.Join(projectDir, "sbom", "bom.cdx.xml")},
+		{name: "absolute output file in the project", outputFile: filepath.Join(projectDir, "bom.cdx.json"), format: "cyclonedx1.6+json", expected: filepath.Join(projectDir, "bom.cdx.json")},
+		{name: "output directory", outputDir: outputDir, format: "cyclonedx1.6+json", expected: filepath.Join(outputDir, "service.cdx.json")},
+		{name: "output directory keeps the file name only", outputDir: outputDir, outputFile: filepath.Join("sbom", "bom.cdx.json"), format: "cyclonedx1.6+json", expected: filepath.Join(outputDir, "bom.cdx.json")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path, err := sbomDocumentPath(tc.outputDir, projectDir, tc.outputFile, tc.format)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, path)
+		})
+	}
+
+	t.Run("output file outside of the project", func(t *testing.T) {
+		_, err := sbomDocumentPath("", projectDir, filepath.Join("..", "bom.cdx.json"), "cyclonedx1.6+json")
+		assert.ErrorContains(t, err, "is outside of")
+
+		_, err = sbomDocumentPath("", projectDir, filepath.Join(outputDir, "bom.cdx.json"), "cyclonedx1.6+json")
+		assert.ErrorContains(t, err, "is outside of")
+	})
+
+	t.Run("output file without the extension of the format", func(t *testing.T) {
+		_, err := sbomDocumentPath("", projectDir, "bom.cdx.json", "spdx2.3+json")
+		assert.ErrorContains(t, err, "must have the extension .spdx.json")
+	})
+}
+
+func TestSnykSbomGenerateHandler(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("mock CLI script uses sh")
+	}
+	fixture := setupTestFixture(t)
+	argsFile := filepath.Join(t.TempDir(), "args")
+	createMockSnykCliWithScript(t, fixture.snykCliPath, fmt.Sprintf(`#!/bin/sh
+echo "$@" > %s
+echo '%s'
+`, argsFile, cycloneDXDocument))
+	tool := getToolWithName(t, fixture.tools, ToolName.SbomGenerate)
+	require.NotNil(t, tool)
+	handler := fixture.binding.snykSbomGenerateHandler(fixture.invocationContext, *tool)
+	projectDir := t.TempDir()
+
+	t.Run("writes the SBOM into the project", func(t *testing.T) {
+		var result SbomGenerateResult
+		output := callToolWithArgs(t, handler, map[string]any{"path": projectDir, "all_projects": true, "exclude": "tests", "output_file": "bom.cdx.json"})
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+
+		assert.Equal(t, filepath.Join(projectDir, "bom.cdx.json"), result.File)
+		assert.Equal(t, "cyclonedx1.6+json", result.Format)
+		assert.Equal(t, 2, result.ComponentCount)
+		document, err := os.ReadFile(result.File)
+		require.NoError(t, err)
+		assert.JSONEq(t, cycloneDXDocument, string(document))
+
+		args, err := os.ReadFile(argsFile)
+		require.NoError(t, err)
+		for _, arg := range []string{"sbom", "--format=cyclonedx1.6+json", "--all-projects", "--exclude=tests"} {
+			assert.Contains(t, string(args), arg)
+		}
+		assert.NotContains(t, string(args), "--output-file")
+	})
+
+	t.Run("does not overwrite a manifest", func(t *testing.T) {
+		manifest := filepath.Join(projectDir, "package.json")
+		require.NoError(t, os.WriteFile(manifest, []byte(`{"name": "app"}`), 0600))
+
+		output := callToolWithArgs(t, handler, map[string]any{"path": projectDir, "output_file": "package.json"})
+
+		assert.Contains(t, output, "Error: output file 'package.json' must have the extension .cdx.json")
+		content, err := os.ReadFile(manifest)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"name": "app"}`, string(content))
+	})
+
+	t.Run("output is not the requested format", func(t *testing.T) {
+		output := callToolWithArgs(t, handler, map[string]any{"path": projectDir, "format": "spdx2.3+json"})
+
+		assert.Contains(t, output, "Error: the CLI output is not an SPDX document")
+		assert.NoFileExists(t, filepath.Join(projectDir, filepath.Base(projectDir)+".spdx.json"))
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		output := callToolWithArgs(t, handler, map[string]any{"path": projectDir, "format": "cyclonedx1.3+json"})
+
+		assert.Contains(t, output, `Error: unsupported SBOM format "cyclonedx1.3+json"`)
+	})
+}
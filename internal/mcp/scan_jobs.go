@@ -0,0 +1,249 @@
+/*
+ * © 2025 Snyk Limited
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// scanJobWorkers is the number of async scan jobs that run at the same time
+	scanJobWorkers = 2
+	// scanJobQueueSize is the number of async scan jobs that can wait for a worker
+	scanJobQueueSize = 32
+	// scanJobRetention is how long finished jobs are kept for retrieval
+	scanJobRetention = time.Hour
+)
+
+type scanJobStatus string
+
+const (
+	scanJobQueued    scanJobStatus = "queued"
+	scanJobRunning   scanJobStatus = "running"
+	scanJobCompleted scanJobStatus = "completed"
+	scanJobFailed    scanJobStatus = "failed"
+)
+
+var (
+	errScanJobQueueFull = errors.New("too many scan jobs queued, please retry later")
+	errScanJobsStopped  = errors.New("the server is shutting down, scan jobs are not run anymore")
+)
+
+// scanJobFunc runs the scan of a job and returns the tool result
+type scanJobFunc func(ctx context.Context) (*mcp.CallToolResult, error)
+
+// scanJobInfo is a snapshot of a job's state as reported to the client
+type scanJobInfo struct {
+	JobID      string        `json:"jobId"`
+	ToolName   string        `json:"toolName"`
+	Path       string        `json:"path"`
+	Status     scanJobStatus `json:"status"`
+	CreatedAt  time.Time     `json:"createdAt"`
+	StartedAt  *time.Time    `json:"startedAt,omitempty"`
+	FinishedAt *time.Time    `json:"finishedAt,omitempty"`
+	Error      string        `json:"error,omitempty"`
+}
+
+type scanJob struct {
+	info   scanJobInfo
+	ctx    context.Context
+	run    scanJobFunc
+	result *mcp.CallToolResult
+}
+
+// scanJobRunner executes scan jobs in the background on a bounded pool of workers.
+// Workers are started with the first submitted job.
+type scanJobRunner struct {
+	mutex     sync.RWMutex
+	jobs      map[string]*scanJob
+	queue     chan *scanJob
+	workers   int
+	startOnce sync.Once
+	ctx       context.Context
+	cancel    context.CancelFunc
+}
+
+func newScanJobRunner(workers int, queueSize int) *scanJobRunner {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &scanJobRunner{
+		jobs:    make(map[string]*scanJob),
+		queue:   make(chan *scanJob, queueSize),
+		workers: workers,
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// submit queues a job and returns its initial state. It fails if the queue is full.
+// The job keeps the values of the given context, e.g. the client session, but not its cancellation,
+// as the request that submitted it returns right away.
+func (r *scanJobRunner) submit(ctx context.Context, toolName string, path string, run scanJobFunc) (scanJobInfo, error) {
+	r.startOnce.Do(r.startWorkers)
+
+	job := &scanJob{
+		info: scanJobInfo{
+			JobID:     uuid.NewString(),
+			ToolName:  toolName,
+			Path:      path,
+			Status:    scanJobQueued,
+			CreatedAt: time.Now().UTC(),
+		},
+		ctx: context.WithoutCancel(ctx),
+		run: run,
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.ctx.Err() != nil {
+		return scanJobInfo{}, errScanJobsStopped
+	}
+	r.pruneFinished()
+	select {
+	case r.queue <- job:
+		r.jobs[job.info.JobID] = job
+		return job.info, nil
+	default:
+		return scanJobInfo{}, errScanJobQueueFull
+	}
+}
+
+// status returns the current state of a job
+func (r *scanJobRunner) status(jobID string) (scanJobInfo, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	job, ok := r.jobs[jobID]
+	if !ok {
+		return scanJobInfo{}, false
+	}
+	return job.info, true
+}
+
+// result returns the state of a job and, once completed, its tool result
+func (r *scanJobRunner) result(jobID string) (scanJobInfo, *mcp.CallToolResult, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	job, ok := r.jobs[jobID]
+	if !ok {
+		return scanJobInfo{}, nil, false
+	}
+	return job.info, job.result, true
+}
+
+// stop cancels running jobs and stops the workers. Queued jobs fail, as they will never run.
+func (r *scanJobRunner) stop() {
+	r.cancel()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for {
+		select {
+		case <-r.queue:
+		default:
+			for _, job := range r.jobs {
+				if job.info.Status == scanJobQueued {
+					r.finish(job, nil, errScanJobsStopped)
+				}
+			}
+			return
+		}
+	}
+}
+
+func (r *scanJobRunner) startWorkers() {
+	for i := 0; i < r.workers; i++ {
+		go r.work()
+	}
+}
+
+func (r *scanJobRunner) work() {
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case job := <-r.queue:
+			r.execute(job)
+		}
+	}
+}
+
+func (r *scanJobRunner) execute(job *scanJob) {
+	r.mutex.Lock()
+	// a worker can still pick a job from the queue while the runner is stopped
+	if job.info.Status != scanJobQueued || r.ctx.Err() != nil {
+		if job.info.Status == scanJobQueued {
+			r.finish(job, nil, errScanJobsStopped)
+		}
+		r.mutex.Unlock()
+		return
+	}
+	startedAt := time.Now().UTC()
+	job.info.Status = scanJobRunning
+	job.info.StartedAt = &startedAt
+	r.mutex.Unlock()
+
+	result, err := r.runSafely(job)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.finish(job, result, err)
+}
+
+// finish records the outcome of a job. Must be called with the mutex held.
+func (r *scanJobRunner) finish(job *scanJob, result *mcp.CallToolResult, err error) {
+	finishedAt := time.Now().UTC()
+	job.info.FinishedAt = &finishedAt
+	job.run = nil
+	job.ctx = nil
+	if err != nil {
+		job.info.Status = scanJobFailed
+		job.info.Error = err.Error()
+		return
+	}
+	job.info.Status = scanJobCompleted
+	job.result = result
+}
+
+// runSafely runs the job and turns a panic into an error, so a failing scan can't take down a worker
+func (r *scanJobRunner) runSafely(job *scanJob) (result *mcp.CallToolResult, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("scan job panicked: %v", recovered)
+		}
+	}()
+	ctx, cancel := context.WithCancel(job.ctx)
+	defer cancel()
+	stopCancelOnShutdown := context.AfterFunc(r.ctx, cancel)
+	defer stopCancelOnShutdown()
+	return job.run(ctx)
+}
+
+// pruneFinished removes finished jobs past the retention period. Must be called with the mutex held.
+func (r *scanJobRunner) pruneFinished() {
+	cutoff := time.Now().Add(-scanJobRetention)
+	for id, job := range r.jobs {
+		if job.info.FinishedAt != nil && job.info.FinishedAt.Before(cutoff) {
+			delete(r.jobs, id)
+		}
+	}
+}
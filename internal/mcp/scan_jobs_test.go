@@ -0,0 +1,224 @@
+/*
+ * © 2025 Snyk Limited
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func waitForJobStatus(t *testing.T, runner *scanJobRunner, jobID string, status scanJobStatus) scanJobInfo {
+	t.Helper()
+	var info scanJobInfo
+	require.Eventually(t, func() bool {
+		var ok bool
+		info, ok = runner.status(jobID)
+		return ok && info.Status == status
+	}, 5*time.Second, 5*time.Millisecond)
+	return info
+}
+
+func callToolWithArgs(t *testing.T, handler func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error), args map[string]any) string {
+	t.Helper()
+	requestJSON, err := json.Marshal(map[string]any{"params": map[string]any{"arguments": args}})
+	require.NoError(t, err)
+	var request mcp.CallToolRequest
+	require.NoError(t, json.Unmarshal(requestJSON, &request))
+
+	result, err := handler(context.Background(), request)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	textContent, ok := result.Content[0].(mcp.TextContent)
+	require.True(t, ok)
+	return textContent.Text
+}
+
+func TestScanJobRunner(t *testing.T) {
+	t.Run("runs job to completion", func(t *testing.T) {
+		runner := newScanJobRunner(1, 1)
+		defer runner.stop()
+
+		job, err := runner.submit(context.Background(), ToolName.CodeTest, "/repo", func(ctx context.Context) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("done"), nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, scanJobQueued, job.Status)
+
+		info := waitForJobStatus(t, runner, job.JobID, scanJobCompleted)
+		assert.NotNil(t, info.StartedAt)
+		assert.NotNil(t, info.FinishedAt)
+		_, result, ok := runner.result(job.JobID)
+		require.True(t, ok)
+		assert.Equal(t, "done", result.Content[0].(mcp.TextContent).Text)
+	})
+
+	t.Run("reports failures and panics", func(t *testing.T) {
+		runner := newScanJobRunner(1, 2)
+		defer runner.stop()
+
+		failing, err := runner.submit(context.Background(), ToolName.ScaTest, "/repo", func(ctx context.Context) (*mcp.CallToolResult, error) {
+			return nil, errors.New("cli crashed")
+		})
+		require.NoError(t, err)
+		panicking, err := runner.submit(context.Background(), ToolName.ScaTest, "/repo", func(ctx context.Context) (*mcp.CallToolResult, error) {
+			panic("boom")
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "cli crashed", waitForJobStatus(t, runner, failing.JobID, scanJobFailed).Error)
+		assert.Contains(t, waitForJobStatus(t, runner, panicking.JobID, scanJobFailed).Error, "boom")
+	})
+
+	t.Run("bounds concurrency and rejects jobs when queue is full", func(t *testing.T) {
+		runner := newScanJobRunner(1, 1)
+		defer runner.stop()
+		release := make(chan struct{})
+		var running atomic.Int32
+		blocking := func(ctx context.Context) (*mcp.CallToolResult, error) {
+			running.Add(1)
+			<-release
+			return mcp.NewToolResultText("done"), nil
+		}
+
+		first, err := runner.submit(context.Background(), ToolName.CodeTest, "/a", blocking)
+		require.NoError(t, err)
+		waitForJobStatus(t, runner, first.JobID, scanJobRunning)
+		second, err := runner.submit(context.Background(), ToolName.CodeTest, "/b", blocking)
+		require.NoError(t, err)
+		_, err = runner.submit(context.Background(), ToolName.CodeTest, "/c", blocking)
+		assert.ErrorIs(t, err, errScanJobQueueFull)
+
+		info, _ := runner.status(second.JobID)
+		assert.Equal(t, scanJobQueued, info.Status)
+		assert.Equal(t, int32(1), running.Load())
+
+		close(release)
+		waitForJobStatus(t, runner, second.JobID, scanJobCompleted)
+	})
+
+	t.Run("job keeps running after submitting context is cancelled", func(t *testing.T) {
+		runner := newScanJobRunner(1, 1)
+		defer runner.stop()
+		ctx, cancel := context.WithCancel(context.Background())
+
+		job, err := runner.submit(ctx, ToolName.CodeTest, "/repo", func(jobCtx context.Context) (*mcp.CallToolResult, error) {
+			cancel()
+			time.Sleep(10 * time.Millisecond)
+			return mcp.NewToolResultText("done"), jobCtx.Err()
+		})
+		require.NoError(t, err)
+
+		waitForJobStatus(t, runner, job.JobID, scanJobCompleted)
+	})
+
+	t.Run("stop cancels running jobs", func(t *testing.T) {
+		runner := newScanJobRunner(1, 1)
+
+		job, err := runner.submit(context.Background(), ToolName.CodeTest, "/repo", func(jobCtx context.Context) (*mcp.CallToolResult, error) {
+			<-jobCtx.Done()
+			return nil, jobCtx.Err()
+		})
+		require.NoError(t, err)
+		waitForJobStatus(t, runner, job.JobID, scanJobRunning)
+
+		runner.stop()
+
+		assert.Contains(t, waitForJobStatus(t, runner, job.JobID, scanJobFailed).Error, "canceled")
+	})
+
+	t.Run("stop fails queued jobs", func(t *testing.T) {
+		runner := newScanJobRunner(1, 1)
+		running, err := runner.submit(context.Background(), ToolName.CodeTest, "/a", func(jobCtx context.Context) (*mcp.CallToolResult, error) {
+			<-jobCtx.Done()
+			return nil, jobCtx.Err()
+		})
+		require.NoError(t, err)
+		waitForJobStatus(t, runner, running.JobID, scanJobRunning)
+		queued, err := runner.submit(context.Background(), ToolName.CodeTest, "/b", func(jobCtx context.Context) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("done"), nil
+		})
+		require.NoError(t, err)
+
+		runner.stop()
+
+		info, ok := runner.status(queued.JobID)
+		require.True(t, ok)
+		assert.Equal(t, scanJobFailed, info.Status)
+		assert.Equal(t, errScanJobsStopped.Error(), info.Error)
+		assert.NotNil(t, info.FinishedAt)
+		assert.Nil(t, info.StartedAt)
+		waitForJobStatus(t, runner, running.JobID, scanJobFailed)
+
+		_, err = runner.submit(context.Background(), ToolName.CodeTest, "/c", func(jobCtx context.Context) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("done"), nil
+		})
+		assert.ErrorIs(t, err, errScanJobsStopped)
+	})
+
+	t.Run("unknown job is not found", func(t *testing.T) {
+		runner := newScanJobRunner(1, 1)
+
+		_, ok := runner.status("unknown")
+		assert.False(t, ok)
+		_, _, ok = runner.result("unknown")
+		assert.False(t, ok)
+	})
+}
+
+func TestAsyncScanTools(t *testing.T) {
+	fixture := setupTestFixture(t)
+	fixture.mockCliOutput(`{"ok": true}`)
+	scanTool := getToolWithName(t, fixture.tools, ToolName.ScaTest)
+	statusTool := getToolWithName(t, fixture.tools, ToolName.ScanStatus)
+	resultTool := getToolWithName(t, fixture.tools, ToolName.ScanResult)
+	require.NotNil(t, scanTool)
+	require.NotNil(t, statusTool)
+	require.NotNil(t, resultTool)
+
+	scanHandler := fixture.binding.defaultHandler(fixture.invocationContext, *scanTool)
+	statusHandler := fixture.binding.snykScanStatusHandler(*statusTool)
+	resultHandler := fixture.binding.snykScanResultHandler(*resultTool)
+
+	submitted := callToolWithArgs(t, scanHandler, map[string]any{"path": t.TempDir(), "async": true})
+
+	var job scanJobInfo
+	require.NoError(t, json.Unmarshal([]byte(submitted), &job))
+	require.NotEmpty(t, job.JobID)
+	assert.Equal(t, ToolName.ScaTest, job.ToolName)
+	assert.Contains(t, submitted, ToolName.ScanStatus)
+
+	require.Eventually(t, func() bool {
+		var status scanJobInfo
+		require.NoError(t, json.Unmarshal([]byte(callToolWithArgs(t, statusHandler, map[string]any{"job_id": job.JobID})), &status))
+		return status.Status == scanJobCompleted
+	}, 5*time.Second, 10*time.Millisecond)
+
+	output := callToolWithArgs(t, resultHandler, map[string]any{"job_id": job.JobID})
+	var scanResult EnhancedScanResult
+	require.NoError(t, json.Unmarshal([]byte(output), &scanResult))
+	assert.True(t, scanResult.Success)
+
+	assert.Contains(t, callToolWithArgs(t, statusHandler, map[string]any{"job_id": "unknown"}), "not found")
+	assert.Contains(t, callToolWithArgs(t, resultHandler, map[string]any{"job_id": "unknown"}), "not found")
+}
@@ -0,0 +1,216 @@
+/*
+ * © 2025 Snyk Limited
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/snyk/studio-mcp/internal/types"
+)
+
+const (
+	scanResourcePrefix        = "snyk://scans/"
+	scanResourceTemplate      = scanResourcePrefix + "{scanId}"
+	scanIssueResourceTemplate = scanResourcePrefix + "{scanId}/issues/{issueId}"
+	scanResourceMimeType      = "application/json"
+)
+
+// storedScan is a completed scan kept in memory so clients can re-read it as a resource
+type storedScan struct {
+	ScanID      string             `json:"scanId"`
+	ToolName    string             `json:"toolName"`
+	Path        string             `json:"path"`
+	CompletedAt time.Time          `json:"completedAt"`
+	Result      EnhancedScanResult `json:"result"`
+}
+
+// scanResultStore holds the latest result per tool and path.
+type scanResultStore struct {
+	mutex sync.RWMutex
+	scans map[string]*storedScan
+}
+
+func newScanResultStore() *scanResultStore {
+	return &scanResultStore{
+		scans: make(map[string]*storedScan),
+	}
+}
+
+// put stores the scan and reports whether a previous result for the same scan ID was replaced
+func (s *scanResultStore) put(scan *storedScan) (replaced bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	_, replaced = s.scans[scan.ScanID]
+	s.scans[scan.ScanID] = scan
+	return replaced
+}
+
+func (s *scanResultStore) get(scanID string) (*storedScan, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	scan, ok := s.scans[scanID]
+	return scan, ok
+}
+
+// scanIdFor derives a stable ID from tool and path, so a rescan of the same path updates the same resource
+func scanIdFor(toolName string, path string) string {
+	sum := sha256.Sum256([]byte(toolName + "\x00" + path))
+	return hex.EncodeToString(sum[:8])
+}
+
+func scanResourceUri(scanID string) string {
+	return scanResourcePrefix + scanID
+}
+
+// issueKey returns the identifier used in issue resource URIs. Fingerprints are preferred as
+// they are unique per finding, whereas rule IDs are shared by all findings of a rule.
+func issueKey(issue types.IssueData) string {
+	if issue.FingerPrint != "" {
+		return issue.FingerPrint
+	}
+	return issue.ID
+}
+
+func issueResourceUri(scanID string, issue types.IssueData) string {
+	return scanResourceUri(scanID) + "/issues/" + url.PathEscape(issueKey(issue))
+}
+
+// addScanResources registers the resource templates under which scan results can be read
+func (m *McpLLMBinding) addScanResources() {
+	m.mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(scanResourceTemplate, "Snyk scan result",
+			mcp.WithTemplateDescription("The latest result of a snyk_code_scan or snyk_sca_scan, including all extracted issues."),
+			mcp.WithTemplateMIMEType(scanResourceMimeType),
+		),
+		m.scanResourceHandler,
+	)
+	m.mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(scanIssueResourceTemplate, "Snyk scan issue",
+			mcp.WithTemplateDescription("A single issue of a Snyk scan result, addressed by its fingerprint or, if none is available, its issue ID."),
+			mcp.WithTemplateMIMEType(scanResourceMimeType),
+		),
+		m.scanIssueResourceHandler,
+	)
+}
+
+// publishScanResult stores the result and exposes it as a resource. A new scan is announced via
+// resources/list_changed, a rescan of a known path via resources/updated to the sessions subscribed to it.
+func (m *McpLLMBinding) publishScanResult(toolName string, path string, result EnhancedScanResult) string {
+	scanID := scanIdFor(toolName, path)
+	uri := scanResourceUri(scanID)
+	replaced := m.scanResults.put(&storedScan{
+		ScanID:      scanID,
+		ToolName:    toolName,
+		Path:        path,
+		CompletedAt: time.Now().UTC(),
+		Result:      result,
+	})
+
+	if m.mcpServer == nil {
+		return uri
+	}
+
+	if !replaced {
+		m.mcpServer.AddResource(
+			mcp.NewResource(uri, fmt.Sprintf("%s result for %s", toolName, path),
+				mcp.WithResourceDescription(fmt.Sprintf("Latest %s result for %s", toolName, path)),
+				mcp.WithMIMEType(scanResourceMimeType),
+			),
+			m.scanResourceHandler,
+		)
+		return uri
+	}
+
+	for _, sessionID := range m.subscriptions.subscribers(uri) {
+		// fails for Streamable HTTP sessions that don't listen for notifications at the moment
+		_ = m.mcpServer.SendNotificationToSpecificClient(sessionID, mcp.MethodNotificationResourceUpdated, map[string]any{"uri": uri})
+	}
+	return uri
+}
+
+func (m *McpLLMBinding) scanResourceHandler(_ context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	scanID := strings.TrimPrefix(request.Params.URI, scanResourcePrefix)
+	scan, ok := m.scanResults.get(scanID)
+	if !ok {
+		return nil, fmt.Errorf("scan not found: %s", scanID)
+	}
+
+	content, err := json.Marshal(scan)
+	if err != nil {
+		return nil, err
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{URI: request.Params.URI, MIMEType: scanResourceMimeType, Text: string(content)},
+	}, nil
+}
+
+func (m *McpLLMBinding) scanIssueResourceHandler(_ context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	scanID := templateArgument(request, "scanId")
+	issueID, err := url.PathUnescape(templateArgument(request, "issueId"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid issue id: %w", err)
+	}
+
+	scan, ok := m.scanResults.get(scanID)
+	if !ok {
+		return nil, fmt.Errorf("scan not found: %s", scanID)
+	}
+
+	var issues []types.IssueData
+	for _, issue := range scan.Result.Issues {
+		if issueKey(issue) == issueID {
+			issues = append(issues, issue)
+		}
+	}
+	if len(issues) == 0 {
+		return nil, fmt.Errorf("issue %s not found in scan %s", issueID, scanID)
+	}
+
+	content, err := json.Marshal(issues)
+	if err != nil {
+		return nil, err
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{URI: request.Params.URI, MIMEType: scanResourceMimeType, Text: string(content)},
+	}, nil
+}
+
+// templateArgument returns a variable matched from the resource template, if any
+func templateArgument(request mcp.ReadResourceRequest, name string) string {
+	value, ok := request.Params.Arguments[name]
+	if !ok {
+		return ""
+	}
+	switch v := value.(type) {
+	case string:
+		return v
+	case []string:
+		if len(v) > 0 {
+			return v[0]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,186 @@
+/*
+ * © 2025 Snyk Limited
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/studio-mcp/internal/types"
+)
+
+type fakeClientSession struct {
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (f *fakeClientSession) Initialize()       {}
+func (f *fakeClientSession) Initialized() bool { return true }
+func (f *fakeClientSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return f.notifications
+}
+func (f *fakeClientSession) SessionID() string { return "fake-session" }
+
+func setupScanResourceBinding(t *testing.T) (*McpLLMBinding, *fakeClientSession) {
+	t.Helper()
+	binding := NewMcpLLMBinding()
+	binding.mcpServer = server.NewMCPServer("Snyk", "1.1.1", server.WithResourceCapabilities(true, true))
+	binding.addScanResources()
+
+	session := &fakeClientSession{notifications: make(chan mcp.JSONRPCNotification, 10)}
+	require.NoError(t, binding.mcpServer.RegisterSession(context.Background(), session))
+	return binding, session
+}
+
+func readResource(t *testing.T, binding *McpLLMBinding, uri string) mcp.JSONRPCMessage {
+	t.Helper()
+	message, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "resources/read",
+		"params":  map[string]any{"uri": uri},
+	})
+	require.NoError(t, err)
+	return binding.mcpServer.HandleMessage(context.Background(), message)
+}
+
+func resourceText(t *testing.T, response mcp.JSONRPCMessage) string {
+	t.Helper()
+	rpcResponse, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok, "expected a successful response, got %#v", response)
+	result, ok := rpcResponse.Result.(mcp.ReadResourceResult)
+	require.True(t, ok)
+	require.Len(t, result.Contents, 1)
+	textContents, ok := result.Contents[0].(mcp.TextResourceContents)
+	require.True(t, ok)
+	return textContents.Text
+}
+
+func testScanResult(issues ...types.IssueData) EnhancedScanResult {
+	return EnhancedScanResult{Success: true, IssueCount: len(issues), Issues: issues}
+}
+
+func TestScanIdFor(t *testing.T) {
+	assert.Equal(t, scanIdFor(ToolName.CodeTest, "/repo"), scanIdFor(ToolName.CodeTest, "/repo"))
+	assert.NotEqual(t, scanIdFor(ToolName.CodeTest, "/repo"), scanIdFor(ToolName.ScaTest, "/repo"))
+	assert.NotEqual(t, scanIdFor(ToolName.CodeTest, "/repo"), scanIdFor(ToolName.CodeTest, "/other"))
+}
+
+func TestPublishScanResult(t *testing.T) {
+	t.Run("new scan is readable and announces list change", func(t *testing.T) {
+		binding, session := setupScanResourceBinding(t)
+
+		uri := binding.publishScanResult(ToolName.ScaTest, "/repo", testScanResult(types.IssueData{ID: "SNYK-JS-1"}))
+
+		assert.Equal(t, scanResourcePrefix+scanIdFor(ToolName.ScaTest, "/repo"), uri)
+		notification := <-session.notifications
+		assert.Equal(t, mcp.MethodNotificationResourcesListChanged, notification.Method)
+
+		var scan storedScan
+		require.NoError(t, json.Unmarshal([]byte(resourceText(t, readResource(t, binding, uri))), &scan))
+		assert.Equal(t, "/repo", scan.Path)
+		assert.Equal(t, ToolName.ScaTest, scan.ToolName)
+		require.Len(t, scan.Result.Issues, 1)
+		assert.Equal(t, "SNYK-JS-1", scan.Result.Issues[0].ID)
+	})
+
+	t.Run("rescan of same path sends resources updated to subscribers", func(t *testing.T) {
+		binding, session := setupScanResourceBinding(t)
+		uri := binding.publishScanResult(ToolName.CodeTest, "/repo", testScanResult())
+		<-session.notifications
+		binding.subscriptions.subscribe(session.SessionID(), uri)
+
+		rescanUri := binding.publishScanResult(ToolName.CodeTest, "/repo", testScanResult(types.IssueData{ID: "rule", FingerPrint: "fp"}))
+
+		assert.Equal(t, uri, rescanUri)
+		notification := <-session.notifications
+		assert.Equal(t, mcp.MethodNotificationResourceUpdated, notification.Method)
+		assert.Equal(t, uri, notification.Params.AdditionalFields["uri"])
+
+		var scan storedScan
+		require.NoError(t, json.Unmarshal([]byte(resourceText(t, readResource(t, binding, uri))), &scan))
+		assert.Equal(t, 1, scan.Result.IssueCount)
+	})
+
+	t.Run("rescan is not sent to sessions that didn't subscribe", func(t *testing.T) {
+		binding, session := setupScanResourceBinding(t)
+		uri := binding.publishScanResult(ToolName.CodeTest, "/repo", testScanResult())
+		<-session.notifications
+		binding.subscriptions.subscribe(session.SessionID(), scanResourceUri(scanIdFor(ToolName.CodeTest, "/other")))
+
+		binding.publishScanResult(ToolName.CodeTest, "/repo", testScanResult())
+
+		assert.Empty(t, session.notifications)
+		_, found := binding.scanResults.get(strings.TrimPrefix(uri, scanResourcePrefix))
+		assert.True(t, found)
+	})
+}
+
+func TestScanIssueResource(t *testing.T) {
+	codeIssue := types.IssueData{ID: "javascript/DangerousEval", FingerPrint: "abc123", FilePath: "app.js", Line: 3}
+	scaIssue := types.IssueData{ID: "SNYK-JS-LODASH-1", PackageName: "lodash"}
+
+	testCases := []struct {
+		name       string
+		issue      types.IssueData
+		expectedID string
+	}{
+		{name: "addressed by fingerprint", issue: codeIssue, expectedID: codeIssue.ID},
+		{name: "addressed by issue id", issue: scaIssue, expectedID: scaIssue.ID},
+		{name: "issue id with slash", issue: types.IssueData{ID: "javascript/NoFingerprint"}, expectedID: "javascript/NoFingerprint"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			binding, _ := setupScanResourceBinding(t)
+			binding.publishScanResult(ToolName.CodeTest, "/repo", testScanResult(codeIssue, scaIssue, tc.issue))
+			scanID := scanIdFor(ToolName.CodeTest, "/repo")
+
+			response := readResource(t, binding, issueResourceUri(scanID, tc.issue))
+
+			var issues []types.IssueData
+			require.NoError(t, json.Unmarshal([]byte(resourceText(t, response)), &issues))
+			require.NotEmpty(t, issues)
+			assert.Equal(t, tc.expectedID, issues[0].ID)
+		})
+	}
+
+	t.Run("unknown issue returns error", func(t *testing.T) {
+		binding, _ := setupScanResourceBinding(t)
+		binding.publishScanResult(ToolName.CodeTest, "/repo", testScanResult(codeIssue))
+		scanID := scanIdFor(ToolName.CodeTest, "/repo")
+
+		response := readResource(t, binding, scanResourceUri(scanID)+"/issues/unknown")
+
+		_, isError := response.(mcp.JSONRPCError)
+		assert.True(t, isError)
+	})
+
+	t.Run("unknown scan returns error", func(t *testing.T) {
+		binding, _ := setupScanResourceBinding(t)
+
+		response := readResource(t, binding, scanResourceUri("doesnotexist"))
+
+		_, isError := response.(mcp.JSONRPCError)
+		assert.True(t, isError)
+	})
+}
@@ -18,31 +18,171 @@ package mcp
 
 import (
 	"encoding/json"
+	"fmt"
+	"slices"
 
 	"github.com/rs/zerolog"
 	"github.com/snyk/studio-mcp/internal/code"
+	"github.com/snyk/studio-mcp/internal/container"
+	"github.com/snyk/studio-mcp/internal/depgraph"
+	"github.com/snyk/studio-mcp/internal/iac"
+	"github.com/snyk/studio-mcp/internal/monitor"
 	"github.com/snyk/studio-mcp/internal/oss"
+	"github.com/snyk/studio-mcp/internal/sarif"
+	"github.com/snyk/studio-mcp/internal/sbom"
+	"github.com/snyk/studio-mcp/internal/secrets"
 	"github.com/snyk/studio-mcp/internal/types"
 )
 
 const (
-	CodeOutputMapper = "CodeOutputMapper"
-	ScaOutputMapper  = "ScaOutputMapper"
+	CodeOutputMapper      = "CodeOutputMapper"
+	ScaOutputMapper       = "ScaOutputMapper"
+	IacOutputMapper       = "IacOutputMapper"
+	ContainerOutputMapper = "ContainerOutputMapper"
+	SecretsOutputMapper   = "SecretsOutputMapper"
+	SbomOutputMapper      = "SbomOutputMapper"
+	// MonitorOutputMapper and IacReportOutputMapper map the projects that tools created or updated in the Snyk platform
+	MonitorOutputMapper   = "MonitorOutputMapper"
+	IacReportOutputMapper = "IacReportOutputMapper"
+	// DependencyTreeOutputMapper maps the dependency graphs printed by the CLI
+	DependencyTreeOutputMapper = "DependencyTreeOutputMapper"
 )
 
+const (
+	// OutputFormatJSON is the enhanced scan result, the default output format
+	OutputFormatJSON = "json"
+	// OutputFormatSarif is a SARIF 2.1.0 log of the mapped issues
+	OutputFormatSarif = "sarif"
+)
+
+// sarifDriverNames are the tool names in SARIF logs of each mapper
+var sarifDriverNames = map[string]string{
+	CodeOutputMapper:      "Snyk Code",
+	ScaOutputMapper:       "Snyk Open Source",
+	IacOutputMapper:       "Snyk IaC",
+	ContainerOutputMapper: "Snyk Container",
+	SecretsOutputMapper:   "Snyk Secrets",
+	SbomOutputMapper:      "Snyk SBOM",
+}
+
 // EnhancedScanResult contains the original scan output and extracted issues
 type EnhancedScanResult struct {
 	OriginalOutput string            `json:"-"`
 	Success        bool              `json:"success"`
 	IssueCount     int               `json:"issueCount"`
 	Issues         []types.IssueData `json:"issues"`
+	ResourceURI    string            `json:"resourceUri,omitempty"`
+	// Filter is set if only the issues in the requested files are reported
+	Filter *IssueFilterSummary `json:"filter,omitempty"`
+	// Baseline is set if only issues introduced since a git baseline are reported
+	Baseline *BaselineComparison `json:"baseline,omitempty"`
+	// BaseImage is set by container scans if the base image is known
+	BaseImage *container.BaseImage `json:"baseImage,omitempty"`
+	// Components groups the issues per SBOM component, set by SBOM scans only
+	Components []sbom.Component `json:"components,omitempty"`
+	// Projects summarizes each scanned project, set by SCA scans only
+	Projects []oss.Project `json:"projects,omitempty"`
+	// FileErrors are the files that the CLI failed to parse or test, set by IaC scans only
+	FileErrors []iac.FileError `json:"fileErrors,omitempty"`
+	// Summary counts all issues, Page tells which of them are returned. Both are set if the result is paged.
+	Summary *IssueSummary `json:"summary,omitempty"`
+	Page    *IssuePage    `json:"page,omitempty"`
+}
+
+// MonitorResult contains the projects that a monitor tool created or updated in the Snyk platform
+type MonitorResult struct {
+	// Success is false if any project could not be monitored
+	Success      bool              `json:"success"`
+	ProjectCount int               `json:"projectCount"`
+	Projects     []monitor.Project `json:"projects"`
+}
+
+// mapMonitorResponse maps the output of a monitor tool to its projects. It returns false if the tool is no monitor tool.
+func mapMonitorResponse(logger *zerolog.Logger, toolDef SnykMcpToolsDefinition, output string, webAppURL string) (string, bool) {
+	var projects []monitor.Project
+	var err error
+	switch toolDef.OutputMapper {
+	case MonitorOutputMapper:
+		projects, err = monitor.ConvertMonitorJsonToProjects([]byte(output))
+	case IacReportOutputMapper:
+		projects, err = monitor.ConvertIacReportJsonToProjects([]byte(output), webAppURL)
+	default:
+		return "", false
+	}
+	if err != nil {
+		logger.Err(err).Msg("Failed to convert monitor output")
+		return output, true
+	}
+
+	result := MonitorResult{Success: true, ProjectCount: len(projects), Projects: projects}
+	for _, project := range projects {
+		if project.Error != "" {
+			result.Success = false
+		}
+	}
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return output, true
+	}
+	return string(resultJSON), true
+}
+
+// DependencyTreeResult contains the dependency tree of each scanned project
+type DependencyTreeResult struct {
+	ProjectCount int             `json:"projectCount"`
+	Projects     []depgraph.Tree `json:"projects"`
+	// MatchCount is the number of packages matching the query, summed over all projects
+	MatchCount *int   `json:"matchCount,omitempty"`
+	Message    string `json:"message,omitempty"`
+}
+
+// mapDependencyTreeResponse maps the dependency graphs printed by the CLI to the dependency trees of the projects,
+// explaining the packages matching the query if set. It returns false if the tool prints no dependency graphs.
+func mapDependencyTreeResponse(logger *zerolog.Logger, toolDef SnykMcpToolsDefinition, output string, query *depgraph.Query) (string, bool) {
+	if toolDef.OutputMapper != DependencyTreeOutputMapper {
+		return "", false
+	}
+	projects, err := depgraph.ParsePrintGraphOutput([]byte(output))
+	if err != nil {
+		logger.Err(err).Msg("Failed to parse dependency graphs")
+		return output, true
+	}
+
+	result := DependencyTreeResult{ProjectCount: len(projects), Projects: make([]depgraph.Tree, 0, len(projects))}
+	matchCount := 0
+	for _, project := range projects {
+		tree := depgraph.Inspect(project, query)
+		matchCount += len(tree.Matches)
+		result.Projects = append(result.Projects, tree)
+	}
+	if query != nil {
+		result.MatchCount = &matchCount
+		if matchCount == 0 {
+			result.Message = fmt.Sprintf("%s is not a dependency of the scanned projects", depgraph.Package{Name: query.PackageName, Version: query.Version})
+		}
+	}
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return output, true
+	}
+	return string(resultJSON), true
 }
 
 // mapScanResponse maps the scan output to an enhanced format for LLMs
 func mapScanResponse(logger *zerolog.Logger, toolDef SnykMcpToolsDefinition, output string, success bool, workDir string, includeIgnores bool) string {
+	result, ok := buildEnhancedScanResult(logger, toolDef, output, success, workDir, includeIgnores)
+	if !ok {
+		return redactUnmappedOutput(toolDef, output)
+	}
+	return marshalEnhancedScanResult(result, output)
+}
+
+// buildEnhancedScanResult extracts the structured scan result. It returns false if the tool has no
+// output mapper or the output cannot be mapped.
+func buildEnhancedScanResult(logger *zerolog.Logger, toolDef SnykMcpToolsDefinition, output string, success bool, workDir string, includeIgnores bool) (EnhancedScanResult, bool) {
 	mapperFunc, ok := outputMapperMap[toolDef.OutputMapper]
 	if !ok || !IsJSON(output) {
-		return output
+		return EnhancedScanResult{}, false
 	}
 
 	result := EnhancedScanResult{
@@ -52,7 +192,20 @@ func mapScanResponse(logger *zerolog.Logger, toolDef SnykMcpToolsDefinition, out
 	}
 
 	mapperFunc(logger, &result, workDir, includeIgnores)
+	return result, true
+}
 
+// redactUnmappedOutput redacts secret values from output that is returned as is, e.g. CLI errors,
+// for tools whose raw output may contain them
+func redactUnmappedOutput(toolDef SnykMcpToolsDefinition, output string) string {
+	if toolDef.OutputMapper != SecretsOutputMapper {
+		return output
+	}
+	return secrets.RedactText(output, nil)
+}
+
+// marshalEnhancedScanResult serializes the result, falling back to the original output on failure
+func marshalEnhancedScanResult(result EnhancedScanResult, output string) string {
 	enhancedJSON, err := json.Marshal(result)
 	if err != nil {
 		return output
@@ -61,15 +214,40 @@ func mapScanResponse(logger *zerolog.Logger, toolDef SnykMcpToolsDefinition, out
 	return string(enhancedJSON)
 }
 
-// extractSCAIssues extracts structured issue data from SCA JSON output
+// marshalSarif converts the issues of the result to a SARIF log
+func marshalSarif(toolDef SnykMcpToolsDefinition, result EnhancedScanResult, workDir string, output string) string {
+	sarifJSON, err := json.Marshal(sarif.ConvertIssuesToSarif(sarifDriverNames[toolDef.OutputMapper], workDir, result.Issues))
+	if err != nil {
+		return output
+	}
+	return string(sarifJSON)
+}
+
+// extractSCAIssues extracts structured issue data and a summary of each scanned project from SCA JSON output
 func extractSCAIssues(logger *zerolog.Logger, result *EnhancedScanResult, workDir string, includeIgnores bool) {
-	issues, err := oss.ConvertOssJsonToIssues(workDir, []byte(result.OriginalOutput), includeIgnores)
+	issues, projects, err := oss.ConvertOssJsonToIssuesAndProjects(workDir, []byte(result.OriginalOutput), includeIgnores)
 	if err != nil {
 		logger.Err(err).Msg("Failed to unmarshal SCA JSON output")
 		return
 	}
 	result.Issues = issues
 	result.IssueCount = len(issues)
+	result.Projects = projects
+}
+
+// cloneScanResult copies the issues and projects of the result, which filters modify in place
+func cloneScanResult(result EnhancedScanResult) EnhancedScanResult {
+	result.Issues = slices.Clone(result.Issues)
+	result.Projects = slices.Clone(result.Projects)
+	return result
+}
+
+// excludeLicenseIssues removes license policy violations from the result
+func excludeLicenseIssues(result *EnhancedScanResult) {
+	result.Issues = slices.DeleteFunc(result.Issues, func(issue types.IssueData) bool {
+		return issue.Type == types.LicenseIssueType
+	})
+	result.IssueCount = len(result.Issues)
 }
 
 // extractSASTIssues extracts issues from SAST scan output
@@ -81,3 +259,54 @@ func extractSASTIssues(logger *zerolog.Logger, result *EnhancedScanResult, workD
 	result.Issues = issues
 	result.IssueCount = len(result.Issues)
 }
+
+// extractIaCIssues extracts structured issue data and the files that failed from IaC JSON output
+func extractIaCIssues(logger *zerolog.Logger, result *EnhancedScanResult, workDir string, includeIgnores bool) {
+	issues, fileErrors, err := iac.ConvertIacJsonToIssues(workDir, []byte(result.OriginalOutput), includeIgnores)
+	if err != nil {
+		logger.Err(err).Msg("Failed to unmarshal IaC JSON output")
+		return
+	}
+	result.Issues = issues
+	result.IssueCount = len(issues)
+	result.FileErrors = fileErrors
+	if len(fileErrors) > 0 {
+		// the files that failed were not tested, so the scan is incomplete
+		result.Success = false
+	}
+}
+
+// extractContainerIssues extracts structured issue data and the base image from container JSON output
+func extractContainerIssues(logger *zerolog.Logger, result *EnhancedScanResult, workDir string, includeIgnores bool) {
+	issues, baseImage, err := container.ConvertContainerJsonToIssues(workDir, []byte(result.OriginalOutput), includeIgnores)
+	if err != nil {
+		logger.Err(err).Msg("Failed to unmarshal container JSON output")
+		return
+	}
+	result.Issues = issues
+	result.IssueCount = len(issues)
+	result.BaseImage = baseImage
+}
+
+// extractSecretsIssues extracts issues from secrets scan output, with the secret values redacted
+func extractSecretsIssues(logger *zerolog.Logger, result *EnhancedScanResult, workDir string, includeIgnores bool) {
+	issues, err := secrets.ConvertSarifJsonToIssues(workDir, []byte(result.OriginalOutput), includeIgnores)
+	if err != nil {
+		logger.Err(err).Msg("Failed to unmarshal secrets SARIF output")
+		return
+	}
+	result.Issues = issues
+	result.IssueCount = len(issues)
+}
+
+// extractSbomIssues extracts structured issue data from SBOM test JSON output, grouped per component
+func extractSbomIssues(logger *zerolog.Logger, result *EnhancedScanResult, workDir string, includeIgnores bool) {
+	issues, components, err := sbom.ConvertSbomJsonToIssues(workDir, []byte(result.OriginalOutput), includeIgnores)
+	if err != nil {
+		logger.Err(err).Msg("Failed to unmarshal SBOM JSON output")
+		return
+	}
+	result.Issues = issues
+	result.IssueCount = len(issues)
+	result.Components = components
+}
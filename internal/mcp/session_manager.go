@@ -0,0 +1,208 @@
+/*
+ * © 2025 Snyk Limited
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	sessionIdPrefix = "snyk-mcp-"
+	// sessionIdleTimeout is how long a session is kept without requests, clients that disconnect
+	// without terminating their session don't send any
+	sessionIdleTimeout = 24 * time.Hour
+	// terminatedSessionRetention is how long a terminated session is still reported as terminated
+	// before it is removed and its ID becomes unknown
+	terminatedSessionRetention = 10 * time.Minute
+)
+
+type sessionState struct {
+	lastSeen     time.Time
+	terminatedAt time.Time
+	clientInfo   mcp.Implementation
+}
+
+func (s *sessionState) expired(now time.Time) bool {
+	if !s.terminatedAt.IsZero() {
+		return now.Sub(s.terminatedAt) > terminatedSessionRetention
+	}
+	return now.Sub(s.lastSeen) > sessionIdleTimeout
+}
+
+// sessionManager issues and tracks Streamable HTTP session IDs.
+// Unlike the library default it only accepts IDs it has issued itself, which lets a client keep
+// using its session ID after reconnecting while rejecting forged or terminated ones. Messages sent
+// while the client was disconnected are not replayed.
+// It also keeps the client info sent with initialize, as mcp-go creates a new session object for
+// every HTTP request. Idle and terminated sessions are removed after a while, so a long-running
+// server doesn't accumulate them.
+type sessionManager struct {
+	mutex    sync.Mutex
+	sessions map[string]*sessionState
+	now      func() time.Time
+	// ended is called with the ID of a session that was terminated or expired, if set
+	ended func(sessionID string)
+}
+
+func newSessionManager() *sessionManager {
+	return &sessionManager{
+		sessions: make(map[string]*sessionState),
+		now:      time.Now,
+	}
+}
+
+func (s *sessionManager) Generate() string {
+	sessionID := sessionIdPrefix + uuid.New().String()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	now := s.now()
+	s.prune(now)
+	s.sessions[sessionID] = &sessionState{lastSeen: now}
+
+	return sessionID
+}
+
+func (s *sessionManager) Validate(sessionID string) (isTerminated bool, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.now()
+	session, known := s.sessions[sessionID]
+	if !known || session.expired(now) {
+		s.remove(sessionID)
+		return false, fmt.Errorf("unknown session id: %s", sessionID)
+	}
+	if !session.terminatedAt.IsZero() {
+		return true, nil
+	}
+	session.lastSeen = now
+	return false, nil
+}
+
+func (s *sessionManager) Terminate(sessionID string) (isNotAllowed bool, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.now()
+	session, known := s.sessions[sessionID]
+	if !known || session.expired(now) {
+		s.remove(sessionID)
+		return false, fmt.Errorf("unknown session id: %s", sessionID)
+	}
+	if session.terminatedAt.IsZero() {
+		session.terminatedAt = now
+		if s.ended != nil {
+			s.ended(sessionID)
+		}
+	}
+	return false, nil
+}
+
+// prune removes expired sessions, the caller must hold the lock
+func (s *sessionManager) prune(now time.Time) {
+	for sessionID, session := range s.sessions {
+		if session.expired(now) {
+			s.remove(sessionID)
+		}
+	}
+}
+
+// remove deletes a known session, the caller must hold the lock
+func (s *sessionManager) remove(sessionID string) {
+	session, known := s.sessions[sessionID]
+	if !known {
+		return
+	}
+	delete(s.sessions, sessionID)
+	if session.terminatedAt.IsZero() && s.ended != nil {
+		s.ended(sessionID)
+	}
+}
+
+func (s *sessionManager) clientInfo(sessionID string) mcp.Implementation {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if session, known := s.sessions[sessionID]; known {
+		return session.clientInfo
+	}
+	return mcp.Implementation{}
+}
+
+func (s *sessionManager) setClientInfo(sessionID string, clientInfo mcp.Implementation) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if session, known := s.sessions[sessionID]; known {
+		session.clientInfo = clientInfo
+	}
+}
+
+// withClientSession replaces the client session of an HTTP request with one that stores its
+// client info in the session manager
+func (s *sessionManager) withClientSession(ctx context.Context, mcpServer *server.MCPServer) context.Context {
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil {
+		return ctx
+	}
+	return mcpServer.WithContext(ctx, &httpClientSession{ClientSession: session, sessions: s})
+}
+
+// httpClientSession wraps the per-request Streamable HTTP session of mcp-go, which has no client info
+type httpClientSession struct {
+	server.ClientSession
+	sessions *sessionManager
+}
+
+var (
+	_ server.SessionWithClientInfo           = (*httpClientSession)(nil)
+	_ server.SessionWithTools                = (*httpClientSession)(nil)
+	_ server.SessionWithStreamableHTTPConfig = (*httpClientSession)(nil)
+)
+
+func (s *httpClientSession) GetClientInfo() mcp.Implementation {
+	return s.sessions.clientInfo(s.SessionID())
+}
+
+func (s *httpClientSession) SetClientInfo(clientInfo mcp.Implementation) {
+	s.sessions.setClientInfo(s.SessionID(), clientInfo)
+}
+
+func (s *httpClientSession) GetSessionTools() map[string]server.ServerTool {
+	if session, ok := s.ClientSession.(server.SessionWithTools); ok {
+		return session.GetSessionTools()
+	}
+	return nil
+}
+
+func (s *httpClientSession) SetSessionTools(tools map[string]server.ServerTool) {
+	if session, ok := s.ClientSession.(server.SessionWithTools); ok {
+		session.SetSessionTools(tools)
+	}
+}
+
+func (s *httpClientSession) UpgradeToSSEWhenReceiveNotification() {
+	if session, ok := s.ClientSession.(server.SessionWithStreamableHTTPConfig); ok {
+		session.UpgradeToSSEWhenReceiveNotification()
+	}
+}
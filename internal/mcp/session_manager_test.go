@@ -0,0 +1,142 @@
+/*
+ * © 2025 Snyk Limited
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package mcp
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionManager(t *testing.T) {
+	t.Run("generated session is valid and active", func(t *testing.T) {
+		manager := newSessionManager()
+
+		sessionID := manager.Generate()
+
+		assert.True(t, strings.HasPrefix(sessionID, sessionIdPrefix))
+		isTerminated, err := manager.Validate(sessionID)
+		require.NoError(t, err)
+		assert.False(t, isTerminated)
+	})
+
+	t.Run("generates unique session ids", func(t *testing.T) {
+		manager := newSessionManager()
+
+		assert.NotEqual(t, manager.Generate(), manager.Generate())
+	})
+
+	t.Run("rejects unknown session id", func(t *testing.T) {
+		manager := newSessionManager()
+
+		_, err := manager.Validate(sessionIdPrefix + "forged")
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects empty session id", func(t *testing.T) {
+		manager := newSessionManager()
+
+		_, err := manager.Validate("")
+		assert.Error(t, err)
+	})
+
+	t.Run("terminated session is reported as terminated", func(t *testing.T) {
+		manager := newSessionManager()
+		sessionID := manager.Generate()
+
+		notAllowed, err := manager.Terminate(sessionID)
+		require.NoError(t, err)
+		assert.False(t, notAllowed)
+
+		isTerminated, err := manager.Validate(sessionID)
+		require.NoError(t, err)
+		assert.True(t, isTerminated)
+	})
+
+	t.Run("terminating unknown session fails", func(t *testing.T) {
+		manager := newSessionManager()
+
+		_, err := manager.Terminate("unknown")
+		assert.Error(t, err)
+	})
+
+	t.Run("terminated session is removed after the retention", func(t *testing.T) {
+		manager := newSessionManager()
+		now := time.Now()
+		manager.now = func() time.Time { return now }
+		sessionID := manager.Generate()
+		_, err := manager.Terminate(sessionID)
+		require.NoError(t, err)
+
+		now = now.Add(terminatedSessionRetention + time.Minute)
+
+		_, err = manager.Validate(sessionID)
+		assert.Error(t, err)
+		assert.Empty(t, manager.sessions)
+	})
+
+	t.Run("idle session is removed when a new session is generated", func(t *testing.T) {
+		manager := newSessionManager()
+		now := time.Now()
+		manager.now = func() time.Time { return now }
+		idleSessionID := manager.Generate()
+		activeSessionID := manager.Generate()
+
+		now = now.Add(sessionIdleTimeout - time.Minute)
+		_, err := manager.Validate(activeSessionID)
+		require.NoError(t, err)
+		now = now.Add(2 * time.Minute)
+		newSessionID := manager.Generate()
+
+		assert.NotContains(t, manager.sessions, idleSessionID)
+		assert.Contains(t, manager.sessions, activeSessionID, "requests keep a session alive")
+		assert.Contains(t, manager.sessions, newSessionID)
+		_, err = manager.Validate(idleSessionID)
+		assert.Error(t, err)
+	})
+	t.Run("reports terminated and expired sessions as ended", func(t *testing.T) {
+		manager := newSessionManager()
+		now := time.Now()
+		manager.now = func() time.Time { return now }
+		var ended []string
+		manager.ended = func(sessionID string) { ended = append(ended, sessionID) }
+		terminatedSessionID := manager.Generate()
+		idleSessionID := manager.Generate()
+
+		_, err := manager.Terminate(terminatedSessionID)
+		require.NoError(t, err)
+		now = now.Add(sessionIdleTimeout + time.Minute)
+		manager.Generate()
+
+		assert.Equal(t, []string{terminatedSessionID, idleSessionID}, ended)
+	})
+
+	t.Run("keeps the client info of a session", func(t *testing.T) {
+		manager := newSessionManager()
+		sessionID := manager.Generate()
+		otherSessionID := manager.Generate()
+		clientInfo := mcp.Implementation{Name: "cursor", Version: "1.2.3"}
+
+		manager.setClientInfo(sessionID, clientInfo)
+
+		assert.Equal(t, clientInfo, manager.clientInfo(sessionID))
+		assert.Empty(t, manager.clientInfo(otherSessionID))
+	})
+}
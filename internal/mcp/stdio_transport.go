@@ -0,0 +1,137 @@
+/*
+ * © 2025 Snyk Limited
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// serveStdio runs the stdio transport until stdin is closed or ctx is done.
+// The mcp-go stdio server handles one message at a time, so a notifications/cancelled would only be
+// read after the tool call it cancels has returned. Tool calls are therefore taken off its input and
+// run concurrently, all other messages are still handled by the stdio server, in order.
+func serveStdio(ctx context.Context, mcpServer *server.MCPServer, subscriptions *resourceSubscriptions, stdin io.Reader, stdout io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	output := &syncWriter{writer: stdout}
+	input := &stdioToolCallDispatcher{reader: bufio.NewReader(stdin), server: mcpServer, subscriptions: subscriptions, output: output}
+	stdioServer := server.NewStdioServer(mcpServer)
+	stdioServer.SetContextFunc(func(sessionCtx context.Context) context.Context {
+		// the context carries the stdio client session, which the tool calls need as well
+		input.ctx = sessionCtx
+		return sessionCtx
+	})
+
+	err := stdioServer.Listen(ctx, input, output)
+	// the client is gone, stop tool calls that are still running
+	cancel()
+	input.calls.Wait()
+	return err
+}
+
+// syncWriter serializes writes of concurrently handled messages, each write being one complete message
+type syncWriter struct {
+	mutex  sync.Mutex
+	writer io.Writer
+}
+
+func (w *syncWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.writer.Write(p)
+}
+
+// stdioToolCallDispatcher is the stdin of the stdio server. It starts tool call requests in their own
+// goroutine and passes all other lines through. The stdio server only reads the next line after handling
+// the previous one, so messages other than tool calls keep their order.
+type stdioToolCallDispatcher struct {
+	reader        *bufio.Reader
+	server        *server.MCPServer
+	subscriptions *resourceSubscriptions
+	output        io.Writer
+	ctx           context.Context
+	pending       []byte
+	calls         sync.WaitGroup
+}
+
+func (d *stdioToolCallDispatcher) Read(p []byte) (int, error) {
+	for len(d.pending) == 0 {
+		line, err := d.reader.ReadBytes('\n')
+		if len(line) > 0 {
+			line = d.handleSubscription(line)
+		}
+		if len(line) > 0 && !d.startToolCall(line) {
+			d.pending = line
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.pending)
+	d.pending = d.pending[n:]
+	return n, nil
+}
+
+// handleSubscription records resource subscriptions of the stdio session
+func (d *stdioToolCallDispatcher) handleSubscription(line []byte) []byte {
+	session := server.ClientSessionFromContext(d.ctx)
+	if session == nil {
+		return line
+	}
+	message := bytes.TrimSpace(line)
+	if handled := d.subscriptions.handle(session.SessionID(), message); !bytes.Equal(handled, message) {
+		return append(handled, '\n')
+	}
+	return line
+}
+
+// startToolCall handles the line in a new goroutine if it is a tools/call request
+func (d *stdioToolCallDispatcher) startToolCall(line []byte) bool {
+	var message struct {
+		ID     any    `json:"id"`
+		Method string `json:"method"`
+	}
+	if err := json.Unmarshal(line, &message); err != nil || message.ID == nil || message.Method != string(mcp.MethodToolsCall) {
+		return false
+	}
+
+	d.calls.Add(1)
+	go func() {
+		defer d.calls.Done()
+		response := d.server.HandleMessage(d.ctx, line)
+		if response == nil {
+			return
+		}
+		responseBytes, err := json.Marshal(response)
+		if err != nil {
+			return
+		}
+		_, _ = fmt.Fprintf(d.output, "%s\n", responseBytes)
+	}()
+	return true
+}
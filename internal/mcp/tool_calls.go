@@ -0,0 +1,176 @@
+/*
+ * © 2025 Snyk Limited
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/snyk/studio-mcp/shared"
+)
+
+const (
+	// DefaultToolTimeoutSeconds is the time after which a tool run is stopped if not configured
+	DefaultToolTimeoutSeconds = 30 * 60
+
+	cancelledNotificationMethod = "notifications/cancelled"
+)
+
+var (
+	errToolTimeout      = errors.New("tool execution timed out")
+	errRequestCancelled = errors.New("request cancelled by client")
+)
+
+// toolCallKey identifies an in-flight tool call. Request IDs are only unique per session.
+type toolCallKey struct {
+	sessionID string
+	requestID string
+}
+
+// toolCallTracker makes in-flight tool calls cancellable by notifications/cancelled.
+// Tool handlers don't get the JSON-RPC request ID, so the before-call hook records it for the
+// request context, which the tool handler middleware then looks up.
+type toolCallTracker struct {
+	mutex   sync.Mutex
+	pending map[context.Context]any
+	calls   map[toolCallKey]context.CancelCauseFunc
+}
+
+func newToolCallTracker() *toolCallTracker {
+	return &toolCallTracker{
+		pending: make(map[context.Context]any),
+		calls:   make(map[toolCallKey]context.CancelCauseFunc),
+	}
+}
+
+func newToolCallKey(ctx context.Context, requestID any) toolCallKey {
+	key := toolCallKey{requestID: fmt.Sprint(requestID)}
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		key.sessionID = session.SessionID()
+	}
+	return key
+}
+
+// hooks returns the server hooks that record request IDs of tool calls
+func (t *toolCallTracker) hooks() *server.Hooks {
+	hooks := &server.Hooks{}
+	hooks.AddBeforeCallTool(func(ctx context.Context, id any, _ *mcp.CallToolRequest) {
+		t.mutex.Lock()
+		defer t.mutex.Unlock()
+		t.pending[ctx] = id
+	})
+	hooks.AddOnError(func(ctx context.Context, _ any, method mcp.MCPMethod, _ any, _ error) {
+		// tool calls that fail before reaching the handler, e.g. unknown tools
+		if method != mcp.MethodToolsCall {
+			return
+		}
+		t.mutex.Lock()
+		defer t.mutex.Unlock()
+		delete(t.pending, ctx)
+	})
+	return hooks
+}
+
+// middleware makes the tool handler's context cancellable by request ID
+func (t *toolCallTracker) middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		t.mutex.Lock()
+		requestID, found := t.pending[ctx]
+		delete(t.pending, ctx)
+		if !found {
+			t.mutex.Unlock()
+			return next(ctx, request)
+		}
+		key := newToolCallKey(ctx, requestID)
+		callCtx, cancel := context.WithCancelCause(ctx)
+		t.calls[key] = cancel
+		t.mutex.Unlock()
+
+		defer func() {
+			t.mutex.Lock()
+			delete(t.calls, key)
+			t.mutex.Unlock()
+			cancel(nil)
+		}()
+		return next(callCtx, request)
+	}
+}
+
+// handleCancelled cancels the tool call named in a notifications/cancelled message
+func (t *toolCallTracker) handleCancelled(ctx context.Context, notification mcp.JSONRPCNotification) {
+	requestID, ok := notification.Params.AdditionalFields["requestId"]
+	if !ok {
+		return
+	}
+	t.mutex.Lock()
+	cancel, found := t.calls[newToolCallKey(ctx, requestID)]
+	t.mutex.Unlock()
+	if found {
+		cancel(errRequestCancelled)
+	}
+}
+
+// toolTimeout returns the timeout for a tool: its own if defined, otherwise the server-wide default
+func toolTimeout(toolDef SnykMcpToolsDefinition, config interface{ GetInt(string) int }) time.Duration {
+	if toolDef.TimeoutSeconds > 0 {
+		return time.Duration(toolDef.TimeoutSeconds) * time.Second
+	}
+	return time.Duration(config.GetInt(shared.ToolTimeoutParam)) * time.Second
+}
+
+// toolInterruption describes why a tool run did not complete
+type toolInterruption struct {
+	Success bool                 `json:"success"`
+	Error   toolInterruptionInfo `json:"error"`
+}
+
+type toolInterruptionInfo struct {
+	Code           string  `json:"code"`
+	Message        string  `json:"message"`
+	Tool           string  `json:"tool"`
+	Path           string  `json:"path,omitempty"`
+	TimeoutSeconds int     `json:"timeoutSeconds,omitempty"`
+	ElapsedSeconds float64 `json:"elapsedSeconds"`
+}
+
+// interruptedToolResult returns a structured error for a tool run that timed out or was cancelled
+func interruptedToolResult(ctx context.Context, toolDef SnykMcpToolsDefinition, workingDir string, timeout time.Duration, elapsed time.Duration) *mcp.CallToolResult {
+	info := toolInterruptionInfo{
+		Code:           "cancelled",
+		Message:        fmt.Sprintf("%s was cancelled before it completed", toolDef.Name),
+		Tool:           toolDef.Name,
+		Path:           workingDir,
+		ElapsedSeconds: elapsed.Round(time.Millisecond).Seconds(),
+	}
+	if errors.Is(context.Cause(ctx), errToolTimeout) {
+		info.Code = "timeout"
+		info.TimeoutSeconds = int(timeout.Seconds())
+		info.Message = fmt.Sprintf("%s did not complete within %s and was stopped. Retry with a narrower path, or run it with `async` if supported.", toolDef.Name, timeout)
+	}
+
+	content, err := json.Marshal(toolInterruption{Success: false, Error: info})
+	if err != nil {
+		return mcp.NewToolResultError(info.Message)
+	}
+	return mcp.NewToolResultError(string(content))
+}
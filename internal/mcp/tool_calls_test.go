@@ -0,0 +1,151 @@
+/*
+ * © 2025 Snyk Limited
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/snyk/go-application-framework/pkg/configuration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/studio-mcp/shared"
+)
+
+func TestToolTimeout(t *testing.T) {
+	config := configuration.NewWithOpts()
+	config.Set(shared.ToolTimeoutParam, 120)
+
+	testCases := []struct {
+		name     string
+		toolDef  SnykMcpToolsDefinition
+		expected time.Duration
+	}{
+		{name: "uses server-wide default", toolDef: SnykMcpToolsDefinition{Name: ToolName.ScaTest}, expected: 2 * time.Minute},
+		{name: "tool timeout overrides default", toolDef: SnykMcpToolsDefinition{Name: ToolName.Version, TimeoutSeconds: 5}, expected: 5 * time.Second},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, toolTimeout(tc.toolDef, config))
+		})
+	}
+
+	t.Run("disabled without default", func(t *testing.T) {
+		assert.Zero(t, toolTimeout(SnykMcpToolsDefinition{Name: ToolName.ScaTest}, configuration.NewWithOpts()))
+	})
+}
+
+func TestInterruptedToolResult(t *testing.T) {
+	toolDef := SnykMcpToolsDefinition{Name: ToolName.ScaTest}
+
+	testCases := []struct {
+		name         string
+		cause        error
+		expectedCode string
+		expectedSecs int
+	}{
+		{name: "timeout", cause: errToolTimeout, expectedCode: "timeout", expectedSecs: 60},
+		{name: "cancelled by client", cause: errRequestCancelled, expectedCode: "cancelled"},
+		{name: "request context done", cause: context.Canceled, expectedCode: "cancelled"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancelCause(context.Background())
+			cancel(tc.cause)
+
+			result := interruptedToolResult(ctx, toolDef, "/repo", time.Minute, 1500*time.Millisecond)
+
+			assert.True(t, result.IsError)
+			var interruption toolInterruption
+			require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &interruption))
+			assert.False(t, interruption.Success)
+			assert.Equal(t, tc.expectedCode, interruption.Error.Code)
+			assert.Equal(t, ToolName.ScaTest, interruption.Error.Tool)
+			assert.Equal(t, "/repo", interruption.Error.Path)
+			assert.Equal(t, tc.expectedSecs, interruption.Error.TimeoutSeconds)
+			assert.InDelta(t, 1.5, interruption.Error.ElapsedSeconds, 0.001)
+		})
+	}
+}
+
+func TestToolCallTrackerCancellation(t *testing.T) {
+	tracker := newToolCallTracker()
+	mcpServer := server.NewMCPServer("Snyk", "1.1.1",
+		server.WithHooks(tracker.hooks()),
+		server.WithToolHandlerMiddleware(tracker.middleware),
+	)
+	mcpServer.AddNotificationHandler(cancelledNotificationMethod, tracker.handleCancelled)
+
+	toolDef := SnykMcpToolsDefinition{Name: ToolName.ScaTest}
+	started := make(chan struct{})
+	mcpServer.AddTool(mcp.NewTool(toolDef.Name), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		close(started)
+		<-ctx.Done()
+		return interruptedToolResult(ctx, toolDef, "", 0, 0), nil
+	})
+
+	stdinReader, stdin := io.Pipe()
+	stdoutReader, stdout := io.Pipe()
+	served := make(chan error, 1)
+	go func() {
+		served <- serveStdio(context.Background(), mcpServer, newResourceSubscriptions(), stdinReader, stdout)
+	}()
+	responses := make(chan string, 1)
+	go func() {
+		line, _ := bufio.NewReader(stdoutReader).ReadString('\n')
+		responses <- line
+	}()
+
+	_, err := io.WriteString(stdin, `{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"snyk_sca_scan"}}`+"\n")
+	require.NoError(t, err)
+	<-started
+	_, err = io.WriteString(stdin, `{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":7,"reason":"user aborted"}}`+"\n")
+	require.NoError(t, err)
+
+	select {
+	case line := <-responses:
+		var response struct {
+			ID     int `json:"id"`
+			Result struct {
+				IsError bool `json:"isError"`
+			} `json:"result"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(line), &response))
+		assert.Equal(t, 7, response.ID)
+		assert.True(t, response.Result.IsError)
+		assert.Contains(t, line, `\"code\":\"cancelled\"`)
+	case <-time.After(5 * time.Second):
+		t.Fatal("tool call was not cancelled")
+	}
+
+	require.NoError(t, stdin.Close())
+	select {
+	case err := <-served:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("stdio server did not stop after stdin was closed")
+	}
+	assert.Empty(t, tracker.calls)
+	assert.Empty(t, tracker.pending)
+}
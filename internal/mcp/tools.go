@@ -30,6 +30,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -45,6 +46,8 @@ import (
 	packageapi "github.com/snyk/studio-mcp/internal/apiclients/package/2024-10-15"
 	"github.com/snyk/studio-mcp/internal/authentication"
 	"github.com/snyk/studio-mcp/internal/breakability"
+	"github.com/snyk/studio-mcp/internal/depgraph"
+	"github.com/snyk/studio-mcp/internal/oss"
 	"github.com/snyk/studio-mcp/internal/package_health"
 	"github.com/snyk/studio-mcp/internal/trust"
 	"github.com/snyk/studio-mcp/internal/types"
@@ -62,25 +65,47 @@ const (
 // ToolName defines all custom tool names.
 // Values must match the "name" field in snyk_tools.json.
 var ToolName = struct {
-	ScaTest       string
-	CodeTest      string
-	Version       string
-	Auth          string
-	Logout        string
-	Trust         string
-	SendFeedback  string
-	PackageHealth string
-	Breakability  string
+	ScaTest          string
+	CodeTest         string
+	Version          string
+	Auth             string
+	Logout           string
+	Trust            string
+	SendFeedback     string
+	PackageHealth    string
+	Breakability     string
+	ScanStatus       string
+	ScanResult       string
+	SecretTest       string
+	IacTest          string
+	Ignore           string
+	ApplyUpgrade     string
+	Monitor          string
+	ContainerMonitor string
+	IacMonitor       string
+	DependencyTree   string
+	SbomGenerate     string
 }{
-	ScaTest:       "snyk_sca_scan",
-	CodeTest:      "snyk_code_scan",
-	Version:       "snyk_version",
-	Auth:          "snyk_auth",
-	Logout:        "snyk_logout",
-	Trust:         "snyk_trust",
-	SendFeedback:  "snyk_send_feedback",
-	PackageHealth: "snyk_package_health_check",
-	Breakability:  "snyk_breakability_check",
+	ScaTest:          "snyk_sca_scan",
+	CodeTest:         "snyk_code_scan",
+	Version:          "snyk_version",
+	Auth:             "snyk_auth",
+	Logout:           "snyk_logout",
+	Trust:            "snyk_trust",
+	SendFeedback:     "snyk_send_feedback",
+	PackageHealth:    "snyk_package_health_check",
+	Breakability:     "snyk_breakability_check",
+	ScanStatus:       "snyk_scan_status",
+	ScanResult:       "snyk_scan_result",
+	SecretTest:       "snyk_secret_scan",
+	IacTest:          "snyk_iac_scan",
+	Ignore:           "snyk_ignore",
+	ApplyUpgrade:     "snyk_apply_upgrade",
+	Monitor:          "snyk_monitor",
+	ContainerMonitor: "snyk_container_monitor",
+	IacMonitor:       "snyk_iac_monitor",
+	DependencyTree:   "snyk_dependency_tree",
+	SbomGenerate:     "snyk_sbom_generate",
 }
 
 type SnykMcpToolAnnotations struct {
@@ -101,6 +126,7 @@ type SnykMcpToolsDefinition struct {
 	OutputMapper   string                 `json:"outputMapper"`
 	Annotations    SnykMcpToolAnnotations `json:"annotations"`
 	Params         []SnykMcpToolParameter `json:"params"`
+	TimeoutSeconds int                    `json:"timeoutSeconds,omitempty"` // overrides the server-wide tool timeout if > 0
 }
 
 type SnykMcpToolParameter struct {
@@ -119,8 +145,12 @@ var snykToolsJson string
 
 var (
 	outputMapperMap = map[string]func(logger *zerolog.Logger, result *EnhancedScanResult, workDir string, includeIgnores bool){
-		ScaOutputMapper:  extractSCAIssues,
-		CodeOutputMapper: extractSASTIssues,
+		ScaOutputMapper:       extractSCAIssues,
+		CodeOutputMapper:      extractSASTIssues,
+		IacOutputMapper:       extractIaCIssues,
+		ContainerOutputMapper: extractContainerIssues,
+		SecretsOutputMapper:   extractSecretsIssues,
+		SbomOutputMapper:      extractSbomIssues,
 	}
 )
 
@@ -181,6 +211,16 @@ func (m *McpLLMBinding) addSnykTools(invocationCtx workflow.InvocationContext, p
 			m.mcpServer.AddTool(tool, m.snykPackageInfoHandler(invocationCtx, toolDef))
 		case ToolName.Breakability:
 			m.mcpServer.AddTool(tool, m.snykBreakabilityHandler(invocationCtx, toolDef))
+		case ToolName.ScanStatus:
+			m.mcpServer.AddTool(tool, m.snykScanStatusHandler(toolDef))
+		case ToolName.ScanResult:
+			m.mcpServer.AddTool(tool, m.snykScanResultHandler(toolDef))
+		case ToolName.Ignore:
+			m.mcpServer.AddTool(tool, m.snykIgnoreHandler(invocationCtx, toolDef))
+		case ToolName.ApplyUpgrade:
+			m.mcpServer.AddTool(tool, m.snykApplyUpgradeHandler(invocationCtx, toolDef))
+		case ToolName.SbomGenerate:
+			m.mcpServer.AddTool(tool, m.snykSbomGenerateHandler(invocationCtx, toolDef))
 		default:
 			m.mcpServer.AddTool(tool, m.defaultHandler(invocationCtx, toolDef))
 		}
@@ -197,7 +237,15 @@ func (m *McpLLMBinding) runSnyk(ctx context.Context, invocationCtx workflow.Invo
 	clientInfo := ClientInfoFromContext(ctx)
 	logger.Debug().Str("clientName", clientInfo.Name).Str("clientVersion", clientInfo.Version).Msg("Found client info")
 
+	release, err := m.cliLimiter.acquire(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
 	command := exec.CommandContext(ctx, cmd[0], cmd[1:]...)
+	// kill the whole process tree on cancellation, as the CLI spawns build tools like gradle or mvn
+	configureProcessGroup(command)
 
 	if workingDir != "" {
 		command.Dir = workingDir
@@ -219,7 +267,7 @@ func (m *McpLLMBinding) runSnyk(ctx context.Context, invocationCtx workflow.Invo
 	// Capture both stdout and stderr to detect error codes like snyk-code-0005
 	// which may be output to stderr by the CLI
 	var stderrBuf bytes.Buffer
-	command.Stderr = io.MultiWriter(logger, &stderrBuf)
+	command.Stderr = io.MultiWriter(logger, &stderrBuf, progressWriterFromContext(ctx))
 	res, err := command.Output()
 	resAsString := string(res)
 
@@ -247,6 +295,46 @@ func (m *McpLLMBinding) runSnyk(ctx context.Context, invocationCtx workflow.Invo
 	return resAsString, nil
 }
 
+// runSnykCached serves the CLI output from the result cache while the scanned inputs are unchanged.
+// With noCache the CLI always runs, and the cache is refreshed with the new output.
+func (m *McpLLMBinding) runSnykCached(ctx context.Context, invocationCtx workflow.InvocationContext, logger *zerolog.Logger, toolName string, workingDir string, cmd []string, noCache bool) (string, error) {
+	if m.resultCache == nil {
+		return m.runSnykShared(ctx, invocationCtx, toolName, workingDir, cmd)
+	}
+
+	org := invocationCtx.GetConfiguration().GetString(configuration.ORGANIZATION)
+	key, cacheable, err := m.resultCache.key(toolName, workingDir, cmd, org)
+	if err != nil {
+		logger.Debug().Err(err).Msg("Failed to compute result cache key, not using cache")
+	}
+	if cacheable && !noCache {
+		if output, ok := m.resultCache.get(key); ok {
+			logger.Debug().Str("toolName", toolName).Str("workingDir", workingDir).Msg("Using cached scan result")
+			progressReporterFromContext(ctx).Report("Using cached scan result")
+			return output, nil
+		}
+	}
+
+	output, err := m.runSnykShared(ctx, invocationCtx, toolName, workingDir, cmd)
+	if err == nil && cacheable {
+		if putErr := m.resultCache.put(key, output); putErr != nil {
+			logger.Debug().Err(putErr).Msg("Failed to store scan result in cache")
+		}
+	}
+	return output, err
+}
+
+// runSnykShared runs a Snyk command like runSnyk, but concurrent identical calls share a single CLI run
+func (m *McpLLMBinding) runSnykShared(ctx context.Context, invocationCtx workflow.InvocationContext, toolName string, workingDir string, cmd []string) (string, error) {
+	output, joined, err := m.cliRuns.do(ctx, cliRunKey(toolName, workingDir, cmd), func(runCtx context.Context) (string, error) {
+		return m.runSnyk(runCtx, invocationCtx, workingDir, cmd)
+	})
+	if joined {
+		m.logger.Debug().Str("toolName", toolName).Str("workingDir", workingDir).Msg("Joined identical CLI run in progress")
+	}
+	return output, err
+}
+
 // nolint: gocyclo, nolintlint // func is used for all scanners, will be refactored to use GAF WFs
 // defaultHandler executes a command and enhances output for scan tools
 func (m *McpLLMBinding) defaultHandler(invocationCtx workflow.InvocationContext, toolDef SnykMcpToolsDefinition) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -262,18 +350,122 @@ func (m *McpLLMBinding) defaultHandler(invocationCtx workflow.InvocationContext,
 		if err != nil {
 			return nil, err
 		}
-		includeIgnores := false
-		if param, exists := params["include-ignores"]; exists && toolDef.Name == ToolName.CodeTest {
+		opts := toolRunOptions{path: workingDir, outputFormat: OutputFormatJSON}
+		if toolDef.OutputMapper == IacReportOutputMapper {
+			opts.webAppURL = invocationCtx.GetEngine().GetConfiguration().GetString(configuration.WEB_APP_URL)
+		}
+		if param, exists := params["path"]; exists {
+			opts.path, _ = param.value.(string)
+		}
+
+		if param, exists := params["no-cache"]; exists {
+			opts.noCache, _ = param.value.(bool)
+			// deleting the key to not include in the CLI run
+			delete(params, "no-cache")
+		}
+
+		async := false
+		if param, exists := params["async"]; exists {
+			async, _ = param.value.(bool)
+			// deleting the key to not include in the CLI run
+			delete(params, "async")
+		}
+
+		if param, exists := params["baseline"]; exists {
+			opts.baseline, _ = param.value.(string)
+			if strings.TrimSpace(opts.baseline) == "" {
+				opts.baseline = DefaultBaselineRef
+			}
+			// deleting the key to not include in the CLI run
+			delete(params, "baseline")
+		}
+
+		if param, exists := params["include-license-issues"]; exists {
+			if value, parsable := param.value.(bool); parsable {
+				opts.excludeLicenseIssues = !value
+			}
+			// deleting the key to not include in the CLI run
+			delete(params, "include-license-issues")
+		}
+
+		if toolDef.OutputMapper == DependencyTreeOutputMapper {
+			if param, exists := params["package-name"]; exists {
+				if name, _ := param.value.(string); strings.TrimSpace(name) != "" {
+					opts.dependencyQuery = &depgraph.Query{PackageName: strings.TrimSpace(name)}
+				}
+				// deleting the key to not include in the CLI run
+				delete(params, "package-name")
+			}
+			if param, exists := params["package-version"]; exists {
+				if version, _ := param.value.(string); opts.dependencyQuery != nil {
+					opts.dependencyQuery.Version = strings.TrimSpace(version)
+				}
+				// deleting the key to not include in the CLI run
+				delete(params, "package-version")
+			}
+		}
+
+		var filePatterns []string
+		if param, exists := params["files"]; exists {
+			if values, parsable := param.value.([]any); parsable {
+				for _, value := range values {
+					if pattern, isString := value.(string); isString && strings.TrimSpace(pattern) != "" {
+						filePatterns = append(filePatterns, pattern)
+					}
+				}
+			}
+			// deleting the key to not include in the CLI run
+			delete(params, "files")
+		}
+		changedOnly := false
+		if param, exists := params["changed-only"]; exists {
+			changedOnly, _ = param.value.(bool)
+			// deleting the key to not include in the CLI run
+			delete(params, "changed-only")
+		}
+
+		cursor := ""
+		if toolDef.OutputMapper != "" {
+			opts.maxIssues = DefaultMaxIssues
+			if param, exists := params["max-issues"]; exists {
+				if value, parsable := param.value.(float64); parsable && value > 0 {
+					opts.maxIssues = int(value)
+				}
+				// deleting the key to not include in the CLI run
+				delete(params, "max-issues")
+			}
+			if param, exists := params["cursor"]; exists {
+				cursor, _ = param.value.(string)
+				// deleting the key to not include in the CLI run
+				delete(params, "cursor")
+			}
+			if param, exists := params["output-format"]; exists {
+				format, _ := param.value.(string)
+				switch strings.ToLower(strings.TrimSpace(format)) {
+				case "", OutputFormatJSON:
+				case OutputFormatSarif:
+					opts.outputFormat = OutputFormatSarif
+				default:
+					return mcp.NewToolResultText(fmt.Sprintf("Error: unsupported output format %q, use %q or %q", format, OutputFormatJSON, OutputFormatSarif)), nil
+				}
+				// deleting the key to not include in the CLI run
+				delete(params, "output-format")
+			}
+		}
+		// the whole result is written to the output directory, and SARIF logs have no pages
+		if invocationCtx.GetConfiguration().IsSet(shared.OutputDirParam) || opts.outputFormat == OutputFormatSarif {
+			opts.maxIssues = 0
+		}
+
+		if param, exists := params["include-ignores"]; exists && (toolDef.Name == ToolName.CodeTest || toolDef.Name == ToolName.SecretTest) {
 			if value, parsable := param.value.(bool); value && parsable {
-				includeIgnores = true
+				opts.includeIgnores = true
 				// deleting the key to not include in the CLI run
 				delete(params, "include-ignores")
 			}
 		}
 
-		trustDisabled := invocationCtx.GetConfiguration().GetBool(trust.DisableTrustFlag) || toolDef.IgnoreTrust
-		if !trustDisabled && !m.folderTrust.IsFolderTrusted(workingDir) {
-			trustErr := fmt.Sprintf("Error: folder '%s' is not trusted. Please run 'snyk_trust' first", workingDir)
+		if trustErr := m.folderTrustError(invocationCtx, toolDef, workingDir); trustErr != "" {
 			logger.Error().Msg(trustErr)
 			return mcp.NewToolResultText(trustErr), nil
 		}
@@ -289,6 +481,16 @@ func (m *McpLLMBinding) defaultHandler(invocationCtx workflow.InvocationContext,
 			return mcp.NewToolResultText("Error: The provided binary name is invalid. Only use the `command` argument for python scanning and provide absolute path of python binary path."), nil
 		}
 
+		// Later pages are served from the stored result without scanning again
+		if cursor != "" {
+			return m.nextIssuePage(toolDef, cursor, opts.maxIssues)
+		}
+
+		opts.filter, err = newIssueFilter(workingDir, filePatterns, changedOnly)
+		if err != nil {
+			return mcp.NewToolResultText(fmt.Sprintf("Error: %s", err.Error())), nil
+		}
+
 		args := buildCommand(m.cliPath, toolDef.Command, params)
 
 		// Add a working directory if specified
@@ -296,45 +498,142 @@ func (m *McpLLMBinding) defaultHandler(invocationCtx workflow.InvocationContext,
 			logger.Debug().Msg("Received empty workingDir")
 		}
 
-		// Run the command
-		output, err := m.runSnyk(ctx, invocationCtx, workingDir, args)
-		success := (err == nil)
+		if async {
+			return m.submitScanJob(ctx, invocationCtx, logger, toolDef, workingDir, args, opts)
+		}
 
-		if err != nil {
-			// No output from CLI, return Err
-			if output == "" {
-				return mcp.NewToolResultText(fmt.Sprintf("Error: %s", err.Error())), nil
-			}
+		progress := newProgressReporter(ctx, &logger, m.mcpServer, request)
+		return m.runTool(ctx, invocationCtx, logger, progress, toolDef, workingDir, args, opts)
+	}
+}
 
-			// Try Snyk Code auto-enable for snyk-code-0005 error
-			if strings.Contains(strings.ToLower(output), CodeAutoEnablementError) && toolDef.Name == ToolName.CodeTest {
-				output, success = m.tryAutoEnableSnykCodeAndRetry(ctx, invocationCtx, &logger, workingDir, args, output, toolDef, includeIgnores)
-			}
+// folderTrustError returns the error to report if the tool may not access the folder, or an empty string if it may
+func (m *McpLLMBinding) folderTrustError(invocationCtx workflow.InvocationContext, toolDef SnykMcpToolsDefinition, folder string) string {
+	trustDisabled := invocationCtx.GetConfiguration().GetBool(trust.DisableTrustFlag) || toolDef.IgnoreTrust
+	if trustDisabled || m.folderTrust.IsFolderTrusted(folder) {
+		return ""
+	}
+	return fmt.Sprintf("Error: folder '%s' is not trusted. Please run 'snyk_trust' first", folder)
+}
 
-			// Return error if not recovered (either non-code-0005 error or failed auto-enable/retry)
-			if !success {
-				return mcp.NewToolResultText(fmt.Sprintf("Error: %s", output)), nil
-			}
+// toolRunOptions are the request parameters that control how the server runs a tool and maps its output,
+// rather than being passed on to the CLI
+type toolRunOptions struct {
+	// path is the scanned path as given in the request
+	path           string
+	includeIgnores bool
+	noCache        bool
+	// baseline is the git ref to compare against, empty if all issues are reported
+	baseline string
+	// maxIssues is the page size of mapped results, 0 returns all issues
+	maxIssues int
+	// filter keeps only the issues in the requested files, nil keeps all issues
+	filter *issueFilter
+	// outputFormat is OutputFormatJSON or OutputFormatSarif
+	outputFormat string
+	// excludeLicenseIssues drops license policy violations from SCA results
+	excludeLicenseIssues bool
+	// webAppURL links the projects reported by IaC, whose output has no project URLs
+	webAppURL string
+	// dependencyQuery selects the packages to explain in dependency trees, nil only lists the direct dependencies
+	dependencyQuery *depgraph.Query
+}
+
+// runTool runs the CLI for a tool and maps its output
+func (m *McpLLMBinding) runTool(ctx context.Context, invocationCtx workflow.InvocationContext, logger zerolog.Logger, progress *progressReporter, toolDef SnykMcpToolsDefinition, workingDir string, args []string, opts toolRunOptions) (*mcp.CallToolResult, error) {
+	// Stop the CLI if it doesn't complete in time, e.g. when dependency resolution hangs
+	timeout := toolTimeout(toolDef, invocationCtx.GetConfiguration())
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, timeout, errToolTimeout)
+		defer cancel()
+	}
+	startedAt := time.Now()
+
+	// Report progress while the CLI runs, if the client asked for it
+	ctx = contextWithProgressReporter(ctx, progress)
+	progress.Report(fmt.Sprintf("Running %s", toolDef.Name))
+	stopHeartbeat := progress.StartHeartbeat()
+	defer stopHeartbeat()
+
+	// Run the command
+	output, err := m.runSnykCached(ctx, invocationCtx, &logger, toolDef.Name, workingDir, args, opts.noCache)
+	stopHeartbeat()
+	if ctx.Err() != nil {
+		logger.Warn().Err(context.Cause(ctx)).Str("toolName", toolDef.Name).Str("workingDir", workingDir).Msg("Tool run interrupted")
+		return interruptedToolResult(ctx, toolDef, workingDir, timeout, time.Since(startedAt)), nil
+	}
+	success := (err == nil)
+
+	if err != nil {
+		// No output from CLI, return Err
+		if output == "" {
+			return mcp.NewToolResultText(fmt.Sprintf("Error: %s", err.Error())), nil
+		}
+
+		// Try Snyk Code auto-enable for snyk-code-0005 error
+		if strings.Contains(strings.ToLower(output), CodeAutoEnablementError) && toolDef.Name == ToolName.CodeTest {
+			output, success = m.tryAutoEnableSnykCodeAndRetry(ctx, invocationCtx, &logger, workingDir, args, output, toolDef, opts.includeIgnores)
+		}
+
+		// Return error if not recovered (either non-code-0005 error or failed auto-enable/retry)
+		if !success {
+			return mcp.NewToolResultText(fmt.Sprintf("Error: %s", redactUnmappedOutput(toolDef, output))), nil
+		}
+	}
+
+	// Scan the baseline to tell new issues from pre-existing ones
+	var baseline *baselineScan
+	if opts.baseline != "" {
+		progress.Report(fmt.Sprintf("Scanning baseline %s", opts.baseline))
+		stopHeartbeat = progress.StartHeartbeat()
+		baseline, err = m.scanBaseline(ctx, invocationCtx, &logger, toolDef, args, opts)
+		stopHeartbeat()
+		if ctx.Err() != nil {
+			return interruptedToolResult(ctx, toolDef, workingDir, timeout, time.Since(startedAt)), nil
+		}
+		if err != nil {
+			logger.Err(err).Str("baseline", opts.baseline).Msg("Failed to scan baseline")
+			return mcp.NewToolResultText(fmt.Sprintf("Error: %s", err.Error())), nil
 		}
+	}
+
+	// Success path: enhance output and handle file output
+	progress.Report("Processing scan results")
+	output = m.enhanceOutput(&logger, toolDef, output, success, workingDir, opts, baseline)
+	return m.handleSuccessOutput(invocationCtx, logger, workingDir, toolDef, output, opts.outputFormat)
+}
 
-		// Success path: enhance output and handle file output
-		output = m.enhanceOutput(&logger, toolDef, output, success, workingDir, includeIgnores)
-		return m.handleSuccessOutput(invocationCtx, logger, workingDir, toolDef, output)
+// submitScanJob runs the tool as a background job and returns the job ID for polling
+func (m *McpLLMBinding) submitScanJob(ctx context.Context, invocationCtx workflow.InvocationContext, logger zerolog.Logger, toolDef SnykMcpToolsDefinition, workingDir string, args []string, opts toolRunOptions) (*mcp.CallToolResult, error) {
+	job, err := m.scanJobs.submit(ctx, toolDef.Name, workingDir, func(jobCtx context.Context) (*mcp.CallToolResult, error) {
+		// progress tokens are bound to the request, which has already returned
+		return m.runTool(jobCtx, invocationCtx, logger, nil, toolDef, workingDir, args, opts)
+	})
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("Error: %s", err.Error())), nil
+	}
+	logger.Debug().Str("jobId", job.JobID).Str("toolName", toolDef.Name).Msg("Submitted async scan job")
+
+	response := struct {
+		scanJobInfo
+		Message string `json:"message"`
+	}{
+		scanJobInfo: job,
+		Message:     fmt.Sprintf("Scan started in the background. Poll %s with the job ID until the status is %s or %s, then fetch the output with %s.", ToolName.ScanStatus, scanJobCompleted, scanJobFailed, ToolName.ScanResult),
 	}
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewToolResultText(string(responseJSON)), nil
 }
 
-func handleFileOutput(logger zerolog.Logger, invocationCtx workflow.InvocationContext, workingDir string, toolDef SnykMcpToolsDefinition, toolOutput string) (string, error) {
-	outputDir := invocationCtx.GetConfiguration().GetString(shared.OutputDirParam)
+// handleFileOutput writes the tool output to the output directory. The output format is used as file extension.
+func handleFileOutput(logger zerolog.Logger, invocationCtx workflow.InvocationContext, workingDir string, toolDef SnykMcpToolsDefinition, toolOutput string, outputFormat string) (string, error) {
 	baseDirName := filepath.Base(workingDir)
-	fileName := fmt.Sprintf("scan_output_%s_%s.json", baseDirName, toolDef.Name)
-	var path string
-	if strings.ToLower(outputDir) == OsTempDir {
-		path = filepath.Join(os.TempDir(), fileName)
-	} else if filepath.IsAbs(outputDir) {
-		path = filepath.Join(outputDir, fileName)
-	} else {
-		path = filepath.Join(workingDir, outputDir, fileName)
-	}
+	fileName := fmt.Sprintf("scan_output_%s_%s.%s", baseDirName, toolDef.Name, outputFormat)
+	path := filepath.Join(outputDirPath(invocationCtx, workingDir), fileName)
 	err := os.WriteFile(path, []byte(toolOutput), 0644)
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to write output to file")
@@ -343,9 +642,66 @@ func handleFileOutput(logger zerolog.Logger, invocationCtx workflow.InvocationCo
 	return path, nil
 }
 
-// enhanceOutput enhances the scan output with structured issue data
-func (m *McpLLMBinding) enhanceOutput(logger *zerolog.Logger, toolDef SnykMcpToolsDefinition, output string, success bool, workDir string, includeIgnores bool) string {
-	return mapScanResponse(logger, toolDef, output, success, workDir, includeIgnores)
+// outputDirPath returns the output directory of the server. A relative directory is resolved against the working directory.
+// It returns an empty string if no output directory is configured.
+func outputDirPath(invocationCtx workflow.InvocationContext, workingDir string) string {
+	outputDir := invocationCtx.GetConfiguration().GetString(shared.OutputDirParam)
+	switch {
+	case outputDir == "":
+		return ""
+	case strings.ToLower(outputDir) == OsTempDir:
+		return os.TempDir()
+	case filepath.IsAbs(outputDir):
+		return outputDir
+	default:
+		return filepath.Join(workingDir, outputDir)
+	}
+}
+
+// enhanceOutput enhances the scan output with structured issue data.
+// License issues are dropped if excluded. With a file filter, only the issues in the requested files are kept.
+// With a baseline scan, only the issues not found in the baseline are kept.
+// Code and SCA results are also published as MCP resources before any issues are dropped, and the resource URI
+// is added to the output.
+// The ignore settings reported by SCA and IaC scans are remembered for snyk_ignore.
+// With a page size, only the first page of issues is returned along with a summary of all issues.
+// In SARIF format, all issues are returned as SARIF log instead.
+// Monitor tools return the projects they created or updated instead of issues, and the dependency tree tool
+// returns the dependency trees of the projects.
+func (m *McpLLMBinding) enhanceOutput(logger *zerolog.Logger, toolDef SnykMcpToolsDefinition, output string, success bool, workDir string, opts toolRunOptions, baseline *baselineScan) string {
+	m.recordIgnoreSettings(toolDef, workDir, output)
+	if monitorOutput, ok := mapMonitorResponse(logger, toolDef, output, opts.webAppURL); ok {
+		return monitorOutput
+	}
+	if treeOutput, ok := mapDependencyTreeResponse(logger, toolDef, output, opts.dependencyQuery); ok {
+		return treeOutput
+	}
+	result, ok := buildEnhancedScanResult(logger, toolDef, output, success, workDir, opts.includeIgnores)
+	if !ok {
+		return redactUnmappedOutput(toolDef, output)
+	}
+	var resourceURI string
+	if toolDef.Name == ToolName.CodeTest || toolDef.Name == ToolName.ScaTest {
+		// the resource holds all issues of the scan, the filters below only apply to this response
+		resourceURI = m.publishScanResult(toolDef.Name, workDir, cloneScanResult(result))
+	}
+	if opts.excludeLicenseIssues {
+		excludeLicenseIssues(&result)
+	}
+	applyIssueFilter(&result, opts.filter, workDir)
+	if baseline != nil {
+		applyBaseline(&result, baseline)
+	}
+	if opts.excludeLicenseIssues || opts.filter != nil || baseline != nil {
+		// project issue counts match the reported issues
+		oss.CountProjectIssues(workDir, result.Projects, result.Issues)
+	}
+	result.ResourceURI = resourceURI
+	if opts.outputFormat == OutputFormatSarif {
+		return marshalSarif(toolDef, result, workDir, output)
+	}
+	m.paginateResult(toolDef.Name, &result, opts.maxIssues)
+	return marshalEnhancedScanResult(result, output)
 }
 
 // tryAutoEnableSnykCodeAndRetry handles Snyk Code enablement with user confirmation.
@@ -418,9 +774,9 @@ If the user says NO:
 }
 
 // handleSuccessOutput handles file output or returns direct output
-func (m *McpLLMBinding) handleSuccessOutput(invocationCtx workflow.InvocationContext, logger zerolog.Logger, workingDir string, toolDef SnykMcpToolsDefinition, output string) (*mcp.CallToolResult, error) {
+func (m *McpLLMBinding) handleSuccessOutput(invocationCtx workflow.InvocationContext, logger zerolog.Logger, workingDir string, toolDef SnykMcpToolsDefinition, output string, outputFormat string) (*mcp.CallToolResult, error) {
 	if invocationCtx.GetConfiguration().IsSet(shared.OutputDirParam) {
-		filePath, fileErr := handleFileOutput(logger, invocationCtx, workingDir, toolDef, output)
+		filePath, fileErr := handleFileOutput(logger, invocationCtx, workingDir, toolDef, output, outputFormat)
 		if fileErr != nil {
 			return nil, fileErr
 		}
@@ -641,14 +997,9 @@ func (m *McpLLMBinding) snykPackageInfoHandler(invocationCtx workflow.Invocation
 
 		// Get org ID from configuration
 		config := invocationCtx.GetEngine().GetConfiguration()
-		orgIdStr := config.GetString(configuration.ORGANIZATION)
-		if orgIdStr == "" {
-			return mcp.NewToolResultText("Error: Organization ID not configured. Please set an organization using 'snyk config set org=<org-id>'"), nil
-		}
-
-		orgId, err := uuid.Parse(orgIdStr)
-		if err != nil {
-			return mcp.NewToolResultText(fmt.Sprintf("Error: Invalid organization ID format: %s", orgIdStr)), nil
+		orgId, orgErr := configuredOrgId(config)
+		if orgErr != "" {
+			return mcp.NewToolResultText(orgErr), nil
 		}
 
 		endpoint, err := url.JoinPath(config.GetString(configuration.API_URL), "rest")
@@ -739,91 +1090,107 @@ func (m *McpLLMBinding) snykBreakabilityHandler(invocationCtx workflow.Invocatio
 		if err != nil {
 			return nil, err
 		}
-		config := invocationCtx.GetEngine().GetConfiguration()
-		orgIdStr := config.GetString(configuration.ORGANIZATION)
-		if orgIdStr == "" {
-			return mcp.NewToolResultText("Error: Organization ID not configured. Please set an organization using 'snyk config set org=<org-id>'"), nil
+		orgId, orgErr := configuredOrgId(invocationCtx.GetEngine().GetConfiguration())
+		if orgErr != "" {
+			return mcp.NewToolResultText(orgErr), nil
 		}
 
-		orgId, err := uuid.Parse(orgIdStr)
-		if err != nil {
-			return mcp.NewToolResultText(fmt.Sprintf("Error: Invalid organization ID format: %s", orgIdStr)), nil
-		}
+		logger.Debug().Str("package", packageName).Str("from", packageFrom).Str("to", packageTo).Msg("Fetching breakability info")
 
-		endpoint, err := url.JoinPath(config.GetString(configuration.API_URL), "hidden")
+		endpoint, err := url.JoinPath(invocationCtx.GetEngine().GetConfiguration().GetString(configuration.API_URL), "hidden")
 		if err != nil {
 			return nil, err
 		}
 
-		httpClient := invocationCtx.GetNetworkAccess().GetHttpClient()
-		apiClient, err := breakabilityapi.NewClientWithResponses(endpoint, breakabilityapi.WithHTTPClient(httpClient))
+		response, err := m.assessBreakability(ctx, invocationCtx, &logger, endpoint, orgId, breakability.PackageUpgrade{
+			Name:        packageName,
+			FromVersion: packageFrom,
+			ToVersion:   packageTo,
+		})
+		if errors.Is(err, errNoAssessment) {
+			// We want the call to fail gracefully. Since the API isn't stable enough to handle load yet.
+			return mcp.NewToolResultText(errNoAssessment.Error()), nil
+		}
 		if err != nil {
-			logger.Error().Err(err).Msg("Failed to create breakability API client")
 			return mcp.NewToolResultText(fmt.Sprintf("Error: Failed to create API client: %s", err.Error())), nil
 		}
 
-		logger.Debug().Str("package", packageName).Str("from", packageFrom).Str("to", packageTo).Msg("Fetching breakability info")
-
-		const breakabilityApiVersion = "2025-11-05"
-
-		upgrades := []breakability.PackageUpgrade{
-			{
-				Name:        packageName,
-				FromVersion: packageFrom,
-				ToVersion:   packageTo,
-			},
+		jsonBytes, err := json.Marshal(response)
+		if err != nil {
+			return mcp.NewToolResultText(fmt.Sprintf("Error: Failed to serialize response: %s", err.Error())), nil
 		}
 
-		reqBody := breakabilityapi.CreateBreakabilityAssessmentsApplicationVndAPIPlusJSONRequestBody{
-			Data: struct {
-				Attributes struct {
-					PackageUpgrades []breakabilityapi.Upgrade `json:"package_upgrades"`
-				} `json:"attributes"`
-				Type breakabilityapi.CreateBreakabilityAssessmentsApplicationVndAPIPlusJSONBodyDataType `json:"type"`
-			}{
-				Type: breakabilityapi.Breakability,
-				Attributes: struct {
-					PackageUpgrades []breakabilityapi.Upgrade `json:"package_upgrades"`
-				}{
-					PackageUpgrades: breakability.ToAPIUpgrades(upgrades),
-				},
-			},
-		}
+		return mcp.NewToolResultText(string(jsonBytes)), nil
+	}
+}
 
-		// We want the call to fail gracefully. Since the API isn't stable enough to handle load yet.
-		const breakabilityErrMsg = "no additional breakability context available"
-		allowPartial := true
-		resp, err := apiClient.CreateBreakabilityAssessmentsWithApplicationVndAPIPlusJSONBodyWithResponse(
-			ctx,
-			orgId,
-			&breakabilityapi.CreateBreakabilityAssessmentsParams{
-				Version:      breakabilityApiVersion,
-				AllowPartial: &allowPartial,
-			},
-			reqBody,
-		)
-		if err != nil {
-			logger.Error().Err(err).Msg("Failed to fetch breakability assessment")
-			return mcp.NewToolResultText(breakabilityErrMsg), nil
-		}
-		if resp.ApplicationvndApiJSON200 == nil {
-			return mcp.NewToolResultText(breakabilityErrMsg), nil
-		}
+// configuredOrgId returns the configured org, or the error to report if it is missing or invalid
+func configuredOrgId(config configuration.Configuration) (uuid.UUID, string) {
+	orgIdStr := config.GetString(configuration.ORGANIZATION)
+	if orgIdStr == "" {
+		return uuid.Nil, "Error: Organization ID not configured. Please set an organization using 'snyk config set org=<org-id>'"
+	}
+	orgId, err := uuid.Parse(orgIdStr)
+	if err != nil {
+		return uuid.Nil, fmt.Sprintf("Error: Invalid organization ID format: %s", orgIdStr)
+	}
+	return orgId, ""
+}
 
-		attrs := breakability.SelectAssessment(resp.ApplicationvndApiJSON200, upgrades[0])
-		if attrs == nil {
-			return mcp.NewToolResultText(breakabilityErrMsg), nil
-		}
+// errNoAssessment is returned if the breakability API failed or has no assessment of the upgrade
+var errNoAssessment = errors.New("no additional breakability context available")
 
-		var response = breakability.BuildBreakabilityResponse(attrs)
+// assessBreakability requests the breakability assessment of a package upgrade from the API at the endpoint.
+// It returns errNoAssessment if no assessment is available, other errors if the API client can't be created.
+func (m *McpLLMBinding) assessBreakability(ctx context.Context, invocationCtx workflow.InvocationContext, logger *zerolog.Logger, endpoint string, orgId uuid.UUID, upgrade breakability.PackageUpgrade) (*breakability.BreakabilityResponse, error) {
+	httpClient := invocationCtx.GetNetworkAccess().GetHttpClient()
+	apiClient, err := breakabilityapi.NewClientWithResponses(endpoint, breakabilityapi.WithHTTPClient(httpClient))
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to create breakability API client")
+		return nil, err
+	}
 
-		jsonBytes, err := json.Marshal(response)
-		if err != nil {
-			return mcp.NewToolResultText(fmt.Sprintf("Error: Failed to serialize response: %s", err.Error())), nil
-		}
+	const breakabilityApiVersion = "2025-11-05"
+
+	reqBody := breakabilityapi.CreateBreakabilityAssessmentsApplicationVndAPIPlusJSONRequestBody{
+		Data: struct {
+			Attributes struct {
+				PackageUpgrades []breakabilityapi.Upgrade `json:"package_upgrades"`
+			} `json:"attributes"`
+			Type breakabilityapi.CreateBreakabilityAssessmentsApplicationVndAPIPlusJSONBodyDataType `json:"type"`
+		}{
+			Type: breakabilityapi.Breakability,
+			Attributes: struct {
+				PackageUpgrades []breakabilityapi.Upgrade `json:"package_upgrades"`
+			}{
+				PackageUpgrades: breakability.ToAPIUpgrades([]breakability.PackageUpgrade{upgrade}),
+			},
+		},
+	}
 
-		return mcp.NewToolResultText(string(jsonBytes)), nil
+	allowPartial := true
+	resp, err := apiClient.CreateBreakabilityAssessmentsWithApplicationVndAPIPlusJSONBodyWithResponse(
+		ctx,
+		orgId,
+		&breakabilityapi.CreateBreakabilityAssessmentsParams{
+			Version:      breakabilityApiVersion,
+			AllowPartial: &allowPartial,
+		},
+		reqBody,
+	)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to fetch breakability assessment")
+		return nil, errNoAssessment
+	}
+	if resp.ApplicationvndApiJSON200 == nil {
+		return nil, errNoAssessment
+	}
+
+	attrs := breakability.SelectAssessment(resp.ApplicationvndApiJSON200, upgrade)
+	if attrs == nil {
+		return nil, errNoAssessment
 	}
+	return breakability.BuildBreakabilityResponse(attrs), nil
 }
 
 func getRequiredStringArg(args map[string]interface{}, name string) (string, error) {
@@ -846,3 +1213,42 @@ func getOptionalStringArg(args map[string]interface{}, name string) string {
 	}
 	return ""
 }
+
+// snykScanStatusHandler reports the state of an async scan job
+func (m *McpLLMBinding) snykScanStatusHandler(toolDef SnykMcpToolsDefinition) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		logger := m.logger.With().Str("method", toolDef.Name).Logger()
+		jobID := request.GetString("job_id", "")
+		logger.Debug().Str("jobId", jobID).Msg("Received call for scan status")
+
+		job, ok := m.scanJobs.status(jobID)
+		if !ok {
+			return mcp.NewToolResultText(fmt.Sprintf("Error: scan job '%s' not found", jobID)), nil
+		}
+		jobJSON, err := json.Marshal(job)
+		if err != nil {
+			return nil, err
+		}
+		return mcp.NewToolResultText(string(jobJSON)), nil
+	}
+}
+
+// snykScanResultHandler returns the output of a finished async scan job
+func (m *McpLLMBinding) snykScanResultHandler(toolDef SnykMcpToolsDefinition) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		logger := m.logger.With().Str("method", toolDef.Name).Logger()
+		jobID := request.GetString("job_id", "")
+		logger.Debug().Str("jobId", jobID).Msg("Received call for scan result")
+
+		job, result, ok := m.scanJobs.result(jobID)
+		switch {
+		case !ok:
+			return mcp.NewToolResultText(fmt.Sprintf("Error: scan job '%s' not found", jobID)), nil
+		case job.Status == scanJobFailed:
+			return mcp.NewToolResultText(fmt.Sprintf("Error: scan job '%s' failed: %s", jobID, job.Error)), nil
+		case job.Status != scanJobCompleted:
+			return mcp.NewToolResultText(fmt.Sprintf("Scan job '%s' is %s. Poll %s until it is %s.", jobID, job.Status, ToolName.ScanStatus, scanJobCompleted)), nil
+		}
+		return result, nil
+	}
+}
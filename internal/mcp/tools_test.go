@@ -1877,7 +1877,7 @@ func TestHandleFileOutput(t *testing.T) {
 		invocationCtx.EXPECT().GetConfiguration().Return(config).AnyTimes()
 
 		workingDir := t.TempDir()
-		filePath, err := handleFileOutput(logger, invocationCtx, workingDir, toolDef, testOutput)
+		filePath, err := handleFileOutput(logger, invocationCtx, workingDir, toolDef, testOutput, OutputFormatJSON)
 
 		require.NoError(t, err)
 		require.NotEmpty(t, filePath)
@@ -1901,7 +1901,7 @@ func TestHandleFileOutput(t *testing.T) {
 		invocationCtx.EXPECT().GetConfiguration().Return(config).AnyTimes()
 
 		workingDir := t.TempDir()
-		filePath, err := handleFileOutput(logger, invocationCtx, workingDir, toolDef, testOutput)
+		filePath, err := handleFileOutput(logger, invocationCtx, workingDir, toolDef, testOutput, OutputFormatJSON)
 
 		require.NoError(t, err)
 		require.NotEmpty(t, filePath)
@@ -1929,7 +1929,7 @@ func TestHandleFileOutput(t *testing.T) {
 		err := os.Mkdir(filepath.Join(workingDir, relativeDir), 0755)
 		require.NoError(t, err)
 
-		filePath, err := handleFileOutput(logger, invocationCtx, workingDir, toolDef, testOutput)
+		filePath, err := handleFileOutput(logger, invocationCtx, workingDir, toolDef, testOutput, OutputFormatJSON)
 
 		require.NoError(t, err)
 		require.NotEmpty(t, filePath)
@@ -1957,7 +1957,7 @@ func TestHandleFileOutput(t *testing.T) {
 		err := os.Mkdir(workingDir, 0755)
 		require.NoError(t, err)
 
-		filePath, err := handleFileOutput(logger, invocationCtx, workingDir, toolDef, testOutput)
+		filePath, err := handleFileOutput(logger, invocationCtx, workingDir, toolDef, testOutput, OutputFormatJSON)
 
 		require.NoError(t, err)
 		require.Contains(t, filePath, "my-project")
@@ -1984,7 +1984,7 @@ func TestHandleFileOutput(t *testing.T) {
 				workingDir := t.TempDir()
 				toolDefLocal := SnykMcpToolsDefinition{Name: tc.toolName}
 
-				filePath, err := handleFileOutput(logger, invocationCtx, workingDir, toolDefLocal, testOutput)
+				filePath, err := handleFileOutput(logger, invocationCtx, workingDir, toolDefLocal, testOutput, OutputFormatJSON)
 
 				require.NoError(t, err)
 				require.Contains(t, filePath, tc.expectedName)
@@ -2003,12 +2003,27 @@ func TestHandleFileOutput(t *testing.T) {
 		invocationCtx.EXPECT().GetConfiguration().Return(config).AnyTimes()
 
 		workingDir := t.TempDir()
-		filePath, err := handleFileOutput(logger, invocationCtx, workingDir, toolDef, testOutput)
+		filePath, err := handleFileOutput(logger, invocationCtx, workingDir, toolDef, testOutput, OutputFormatJSON)
 
 		require.Error(t, err)
 		require.Empty(t, filePath)
 	})
 
+	t.Run("WriteSarifFile", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		invocationCtx := mocks.NewMockInvocationContext(ctrl)
+		config := configuration.New()
+		outputDir := t.TempDir()
+		config.Set(shared.OutputDirParam, outputDir)
+		invocationCtx.EXPECT().GetConfiguration().Return(config).AnyTimes()
+
+		workingDir := t.TempDir()
+		filePath, err := handleFileOutput(logger, invocationCtx, workingDir, toolDef, testOutput, OutputFormatSarif)
+
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(outputDir, "scan_output_"+filepath.Base(workingDir)+"_snyk_code_scan.sarif"), filePath)
+	})
+
 	t.Run("CaseInsensitiveTempCheck", func(t *testing.T) {
 		tempVariants := []string{OsTempDir, OsTempDir, OsTempDir, OsTempDir}
 
@@ -2021,7 +2036,7 @@ func TestHandleFileOutput(t *testing.T) {
 				invocationCtx.EXPECT().GetConfiguration().Return(config).AnyTimes()
 
 				workingDir := t.TempDir()
-				filePath, err := handleFileOutput(logger, invocationCtx, workingDir, toolDef, testOutput)
+				filePath, err := handleFileOutput(logger, invocationCtx, workingDir, toolDef, testOutput, OutputFormatJSON)
 
 				require.NoError(t, err)
 				require.Contains(t, filePath, os.TempDir())
@@ -2194,7 +2209,7 @@ func TestToolProfileAssignmentsInJson(t *testing.T) {
 
 			// Verify profile-based filtering works for each tool
 			switch tool.Name {
-			case "snyk_auth", "snyk_sca_scan", "snyk_code_scan", "snyk_version", "snyk_logout", "snyk_trust", "snyk_send_feedback":
+			case "snyk_auth", "snyk_sca_scan", "snyk_code_scan", "snyk_version", "snyk_logout", "snyk_trust", "snyk_send_feedback", "snyk_scan_status", "snyk_scan_result":
 				// These should be in lite profile
 				require.True(t, IsToolInProfile(tool, ProfileLite),
 					"Tool %s should be in lite profile", tool.Name)
@@ -2203,7 +2218,7 @@ func TestToolProfileAssignmentsInJson(t *testing.T) {
 				require.True(t, IsToolInProfile(tool, ProfileExperimental),
 					"Tool %s should be in experimental profile", tool.Name)
 
-			case "snyk_container_scan", "snyk_iac_scan", "snyk_sbom_scan", "snyk_aibom", "snyk_package_health_check", "snyk_breakability_check":
+			case "snyk_container_scan", "snyk_iac_scan", "snyk_sbom_scan", "snyk_aibom", "snyk_package_health_check", "snyk_breakability_check", "snyk_ignore", "snyk_apply_upgrade", "snyk_monitor", "snyk_container_monitor", "snyk_iac_monitor", "snyk_dependency_tree", "snyk_sbom_generate":
 				// These should be in full but not lite
 				require.False(t, IsToolInProfile(tool, ProfileLite),
 					"Tool %s should NOT be in lite profile", tool.Name)
@@ -2568,6 +2583,24 @@ func TestSnykBreakabilityHandler_GracefulFailure(t *testing.T) {
 	})
 }
 
+func TestSnykBreakabilityHandler_InvalidApiUrl(t *testing.T) {
+	fixture := setupTestFixture(t)
+	toolDef := getToolWithName(t, fixture.tools, ToolName.Breakability)
+	require.NotNil(t, toolDef)
+	configureBreakabilityFixture(t, fixture, "http://[::1", "44444444-4444-4444-4444-444444444444")
+
+	handler := fixture.binding.snykBreakabilityHandler(fixture.invocationContext, *toolDef)
+
+	result, err := handler(t.Context(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"package_name":         "lodash",
+		"package_version_from": "4.17.10",
+		"package_version_to":   "4.17.21",
+	}}})
+
+	require.Error(t, err)
+	require.Nil(t, result)
+}
+
 func TestSnykBreakabilityHandler_Unauthenticated(t *testing.T) {
 	// When WhoAmI fails, the handler should bail out with a friendly auth message.
 	engine, engineConfig := SetupEngineMock(t)
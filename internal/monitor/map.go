@@ -0,0 +1,106 @@
+package monitor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Project is a project that was created or updated in the Snyk platform
+type Project struct {
+	Name       string `json:"name,omitempty"`
+	ID         string `json:"id,omitempty"`
+	URL        string `json:"url,omitempty"`
+	Path       string `json:"path,omitempty"`
+	TargetFile string `json:"targetFile,omitempty"`
+	// Error is set if the project could not be monitored
+	Error string `json:"error,omitempty"`
+}
+
+// monitorResult is a project in the output of `snyk monitor --json` and `snyk container monitor --json`
+type monitorResult struct {
+	ID          string `json:"id"`
+	URI         string `json:"uri"`
+	ProjectName string `json:"projectName"`
+	Path        string `json:"path"`
+	TargetFile  string `json:"targetFile"`
+	Error       string `json:"error"`
+}
+
+// iacReportResult is a tested file in the output of `snyk iac test --report --json`
+type iacReportResult struct {
+	ProjectName string `json:"projectName"`
+	TargetFile  string `json:"targetFile"`
+	Path        string `json:"path"`
+	Org         string `json:"org"`
+	Error       string `json:"error"`
+}
+
+// ConvertMonitorJsonToProjects converts the output of `snyk monitor --json` into the monitored projects
+func ConvertMonitorJsonToProjects(res []byte) ([]Project, error) {
+	results, err := unmarshalResults[monitorResult](res)
+	if err != nil {
+		return nil, err
+	}
+
+	projects := make([]Project, 0, len(results))
+	for _, result := range results {
+		project := Project{
+			Name:       result.ProjectName,
+			ID:         result.ID,
+			URL:        result.URI,
+			Path:       result.Path,
+			TargetFile: result.TargetFile,
+			Error:      result.Error,
+		}
+		if project.Error == "" && project.URL == "" {
+			project.Error = "the project was not monitored"
+		}
+		projects = append(projects, project)
+	}
+	return projects, nil
+}
+
+// ConvertIacReportJsonToProjects converts the output of `snyk iac test --report --json` into the reported projects.
+// The output doesn't contain project IDs, so like the CLI, the URL links the projects of the organization.
+func ConvertIacReportJsonToProjects(res []byte, webAppURL string) ([]Project, error) {
+	results, err := unmarshalResults[iacReportResult](res)
+	if err != nil {
+		return nil, err
+	}
+
+	projects := make([]Project, 0, len(results))
+	for _, result := range results {
+		project := Project{
+			Name:       result.ProjectName,
+			Path:       result.Path,
+			TargetFile: result.TargetFile,
+			Error:      result.Error,
+		}
+		if project.Error == "" && result.Org != "" && webAppURL != "" {
+			project.URL = fmt.Sprintf("%s/org/%s/projects", strings.TrimSuffix(webAppURL, "/"), url.PathEscape(result.Org))
+		}
+		projects = append(projects, project)
+	}
+	return projects, nil
+}
+
+// unmarshalResults unmarshals a single result, or the array of results of multiple projects
+func unmarshalResults[T any](res []byte) ([]T, error) {
+	output := strings.TrimSpace(string(res))
+	var results []T
+	if strings.HasPrefix(output, "[") {
+		if err := json.Unmarshal(res, &results); err != nil {
+			return nil, errors.Join(err, fmt.Errorf("couldn't unmarshal CLI response. Input: %s", output))
+		}
+		return results, nil
+	}
+
+	var result T
+	if err := json.Unmarshal(res, &result); err != nil {
+		return nil, errors.Join(err, fmt.Errorf("couldn't unmarshal CLI response. Input: %s", output))
+	}
+	return append(results, result), nil
+}
@@ -0,0 +1,56 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertMonitorJsonToProjects(t *testing.T) {
+	t.Run("single project", func(t *testing.T) {
+		output := `{"ok": true, "org": "my-org", "id": "1111", "isMonitored": true, "uri": "https://app.snyk.io/org/my-org/project/1111/history/2222", "projectName": "app", "path": "/repo"}`
+
+		projects, err := ConvertMonitorJsonToProjects([]byte(output))
+		require.NoError(t, err)
+
+		assert.Equal(t, []Project{{Name: "app", ID: "1111", URL: "https://app.snyk.io/org/my-org/project/1111/history/2222", Path: "/repo"}}, projects)
+	})
+
+	t.Run("all projects with a failed project", func(t *testing.T) {
+		output := `[
+			{"ok": true, "id": "1111", "uri": "https://app.snyk.io/org/my-org/project/1111", "projectName": "app", "path": "/repo"},
+			{"ok": false, "error": "Could not find a lockfile", "path": "/repo/web"},
+			{"ok": true, "path": "/repo/docs"}
+		]`
+
+		projects, err := ConvertMonitorJsonToProjects([]byte(output))
+		require.NoError(t, err)
+
+		require.Len(t, projects, 3)
+		assert.Equal(t, "https://app.snyk.io/org/my-org/project/1111", projects[0].URL)
+		assert.Empty(t, projects[0].Error)
+		assert.Equal(t, "Could not find a lockfile", projects[1].Error)
+		assert.Equal(t, "the project was not monitored", projects[2].Error)
+	})
+
+	t.Run("invalid output", func(t *testing.T) {
+		_, err := ConvertMonitorJsonToProjects([]byte("Monitoring /repo..."))
+		assert.Error(t, err)
+	})
+}
+
+func TestConvertIacReportJsonToProjects(t *testing.T) {
+	output := `[
+		{"ok": false, "org": "my org", "projectName": "infra", "targetFile": "main.tf", "path": "/repo/infra", "infrastructureAsCodeIssues": []},
+		{"ok": false, "error": "Failed to parse Terraform file", "path": "/repo/infra/broken.tf"}
+	]`
+
+	projects, err := ConvertIacReportJsonToProjects([]byte(output), "https://app.snyk.io/")
+	require.NoError(t, err)
+
+	require.Len(t, projects, 2)
+	assert.Equal(t, Project{Name: "infra", URL: "https://app.snyk.io/org/my%20org/projects", Path: "/repo/infra", TargetFile: "main.tf"}, projects[0])
+	assert.Equal(t, "Failed to parse Terraform file", projects[1].Error)
+	assert.Empty(t, projects[1].URL)
+}
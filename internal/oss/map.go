@@ -1,6 +1,8 @@
 package oss
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -22,28 +24,109 @@ var lockFilesToManifestMap = map[string]string{
 	"poetry.lock":       "pyproject.toml",
 }
 
+// Project summarizes the scan of one project, as found by `--all-projects`
+type Project struct {
+	Name            string `json:"name,omitempty"`
+	PackageManager  string `json:"packageManager,omitempty"`
+	TargetFile      string `json:"targetFile,omitempty"`
+	DependencyCount int    `json:"dependencyCount"`
+	Ok              bool   `json:"ok"`
+	IssueCount      int    `json:"issueCount"`
+	// Error and Path are set if the project could not be scanned
+	Error string `json:"error,omitempty"`
+	Path  string `json:"path,omitempty"`
+}
+
 func ConvertOssJsonToIssues(workDir string, res []byte, includeIgnores bool) ([]types.IssueData, error) {
+	issues, _, err := ConvertOssJsonToIssuesAndProjects(workDir, res, includeIgnores)
+	return issues, err
+}
+
+// ConvertOssJsonToIssuesAndProjects converts the CLI output into issues and a summary of each scanned project
+func ConvertOssJsonToIssuesAndProjects(workDir string, res []byte, includeIgnores bool) ([]types.IssueData, []Project, error) {
 	output := string(res)
 	var scanResults []ScanResult
 	if strings.HasPrefix(output, "[") {
 		err := json.Unmarshal(res, &scanResults)
 		if err != nil {
 			err = errors.Join(err, fmt.Errorf("couldn't unmarshal CLI response. Input: %s", output))
-			return nil, err
+			return nil, nil, err
 		}
 	} else {
 		var result ScanResult
 		err := json.Unmarshal(res, &result)
 		if err != nil {
 			err = errors.Join(err, fmt.Errorf("couldn't unmarshal CLI response. Input: %s", output))
-			return nil, err
+			return nil, nil, err
 		}
 		scanResults = append(scanResults, result)
 	}
 
 	issues := ConvertToIssue(workDir, scanResults, includeIgnores)
 
-	return issues, nil
+	projects := make([]Project, 0, len(scanResults))
+	for _, res := range scanResults {
+		project := Project{
+			Name:            res.ProjectName,
+			PackageManager:  res.PackageManager,
+			TargetFile:      res.DisplayTargetFile,
+			DependencyCount: res.DependencyCount,
+			Ok:              res.Ok,
+		}
+		if res.Error != "" {
+			project.Error = res.Error
+			project.Path = res.Path
+		}
+		projects = append(projects, project)
+	}
+	CountProjectIssues(workDir, projects, issues)
+
+	return issues, projects, nil
+}
+
+// CountProjectIssues sets the issue count of each project to the number of issues in its target file
+func CountProjectIssues(workDir string, projects []Project, issues []types.IssueData) {
+	counts := map[string]int{}
+	for _, issue := range issues {
+		counts[issue.FilePath]++
+	}
+	for i := range projects {
+		projects[i].IssueCount = counts[getAbsTargetFilePath(workDir, projects[i].TargetFile)]
+	}
+}
+
+// ParseIgnoreSettings returns the ignore settings reported in the CLI output of `snyk test` or `snyk iac test`.
+// The settings of all projects are combined so that the most restrictive setting applies.
+// It returns false if the output reports no ignore settings.
+func ParseIgnoreSettings(res []byte) (IgnoreSettings, bool) {
+	type withIgnoreSettings struct {
+		IgnoreSettings *IgnoreSettings `json:"ignoreSettings"`
+	}
+	var results []withIgnoreSettings
+	if strings.HasPrefix(strings.TrimSpace(string(res)), "[") {
+		if err := json.Unmarshal(res, &results); err != nil {
+			return IgnoreSettings{}, false
+		}
+	} else {
+		var result withIgnoreSettings
+		if err := json.Unmarshal(res, &result); err != nil {
+			return IgnoreSettings{}, false
+		}
+		results = append(results, result)
+	}
+
+	var settings IgnoreSettings
+	found := false
+	for _, result := range results {
+		if result.IgnoreSettings == nil {
+			continue
+		}
+		found = true
+		settings.AdminOnly = settings.AdminOnly || result.IgnoreSettings.AdminOnly
+		settings.ReasonRequired = settings.ReasonRequired || result.IgnoreSettings.ReasonRequired
+		settings.DisregardFilesystemIgnores = settings.DisregardFilesystemIgnores || result.IgnoreSettings.DisregardFilesystemIgnores
+	}
+	return settings, found
 }
 
 func ConvertToIssue(workDir string, scanResults []ScanResult, includeIgnores bool) []types.IssueData {
@@ -55,25 +138,119 @@ func ConvertToIssue(workDir string, scanResults []ScanResult, includeIgnores boo
 	return allIssues
 }
 
+// convertScanResultToIssues converts the vulnerabilities of one project. The CLI reports a vulnerability once per
+// dependency path, these are merged into one issue that lists all paths.
 func convertScanResultToIssues(workDir string, res *ScanResult, includeIgnores bool) []types.IssueData {
 	var issues []types.IssueData
-	duplicateCheckMap := map[string]bool{}
+	var paths [][][]string
+	issueIndexes := map[string]int{}
+	targetFilePath := getAbsTargetFilePath(workDir, res.DisplayTargetFile)
 
 	for _, issue := range res.Vulnerabilities {
 		if !includeIgnores && issue.IsIgnored {
 			continue
 		}
-		targetFilePath := getAbsTargetFilePath(workDir, res.DisplayTargetFile)
-		duplicateKey := string(targetFilePath) + "|" + issue.Id + "|" + issue.PackageName
-		if duplicateCheckMap[duplicateKey] {
+		duplicateKey := targetFilePath + "|" + issue.Id + "|" + issue.PackageName + "|" + issue.Version
+		if index, found := issueIndexes[duplicateKey]; found {
+			paths[index] = appendPath(paths[index], dependencyPath(issue))
+			// a vulnerability that is also a direct dependency can be fixed in the manifest
+			if transitive := isTransitiveDependency(issue); transitive != nil && !*transitive {
+				issues[index].IsTransitiveDependency = transitive
+			}
 			continue
 		}
 		snykIssue := toIssue(issue, targetFilePath)
+		applyLicensePolicy(snykIssue, issue, res.LicensesPolicy)
+		issueIndexes[duplicateKey] = len(issues)
 		issues = append(issues, *snykIssue)
-		duplicateCheckMap[duplicateKey] = true
+		paths = append(paths, appendPath(nil, dependencyPath(issue)))
+	}
+
+	for i := range issues {
+		if len(paths[i]) > 1 {
+			issues[i].DependencyPaths = paths[i]
+		}
+		issues[i].FingerPrint = fingerprint(workDir, issues[i], paths[i])
+		issues[i].PackageFingerPrint = packageFingerprint(workDir, issues[i])
 	}
 	return issues
+}
 
+// applyLicensePolicy marks license issues and applies the severity and instructions of the org's license policy
+func applyLicensePolicy(d *types.IssueData, issue ossIssue, policy licensesPolicy) {
+	if issue.Type != types.LicenseIssueType {
+		return
+	}
+	d.Type = types.LicenseIssueType
+	d.License = issue.License
+	if rule, ok := policy.OrgLicenseRules[issue.License]; ok {
+		if rule.Severity != "" {
+			d.Severity = rule.Severity
+		}
+		if rule.Instructions != "" {
+			d.Remediation = rule.Instructions
+		}
+	}
+
+	d.Message = fmt.Sprintf("%s license in package %s@%s. %s", issue.License, issue.PackageName, issue.Version, d.Remediation)
+	const maxLength = 200
+	if len(d.Message) > maxLength {
+		d.Message = d.Message[:maxLength] + "... (Snyk)"
+	}
+}
+
+// dependencyPath is the dependency chain from the direct dependency to the vulnerable package
+func dependencyPath(issue ossIssue) []string {
+	if len(issue.From) < 2 {
+		return nil
+	}
+	return slices.Clone(issue.From[1:])
+}
+
+func appendPath(paths [][]string, path []string) [][]string {
+	if path == nil {
+		return paths
+	}
+	for _, existing := range paths {
+		if slices.Equal(existing, path) {
+			return paths
+		}
+	}
+	return append(paths, path)
+}
+
+// fingerprint identifies an issue across scans and machines: it is derived from the vulnerability, the package
+// version, the target file relative to the scanned directory and the dependency paths, ignoring their order.
+func fingerprint(workDir string, issue types.IssueData, paths [][]string) string {
+	joinedPaths := make([]string, 0, len(paths))
+	for _, path := range paths {
+		joinedPaths = append(joinedPaths, strings.Join(path, ">"))
+	}
+	slices.Sort(joinedPaths)
+	return hashParts(issue.ID, issue.PackageName, issue.Version, relativeTargetFile(workDir, issue), strings.Join(joinedPaths, "|"))
+}
+
+// packageFingerprint identifies the vulnerable package version across scans regardless of the dependency paths
+// it is introduced through, so that a new path to an existing vulnerability doesn't make it a new issue
+func packageFingerprint(workDir string, issue types.IssueData) string {
+	return hashParts(issue.ID, issue.PackageName, issue.Version, relativeTargetFile(workDir, issue))
+}
+
+func relativeTargetFile(workDir string, issue types.IssueData) string {
+	targetFile := issue.FilePath
+	if relPath, err := filepath.Rel(workDir, targetFile); workDir != "" && filepath.IsAbs(targetFile) && err == nil {
+		targetFile = relPath
+	}
+	return filepath.ToSlash(targetFile)
+}
+
+func hashParts(parts ...string) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
 }
 
 func toIssue(issue ossIssue, targetFilePath string) *types.IssueData {
@@ -107,6 +284,7 @@ func toIssue(issue ossIssue, targetFilePath string) *types.IssueData {
 		IsIgnored:              issue.IsIgnored,
 		IsTransitiveDependency: isTransitiveDependency(issue),
 		IntroducedThrough:      introducedThroughChain(issue),
+		DockerfileInstruction:  issue.DockerfileInstruction,
 	}
 
 	return d
@@ -336,3 +336,134 @@ func TestIssueData_JSONSerialization_IntroducedThroughOmitempty(t *testing.T) {
 	require.NotNil(t, roundTrip.IntroducedThrough)
 	assert.True(t, slices.Equal([]string{"express@4", "lodash@1"}, roundTrip.IntroducedThrough))
 }
+
+func TestConvertScanResultToIssues_MergesDependencyPaths(t *testing.T) {
+	vulnerability := func(version string, from ...string) ossIssue {
+		return ossIssue{Id: "SNYK-JS-LODASH-1", Title: "Prototype Pollution", Severity: "high", PackageName: "lodash", Version: version, From: from}
+	}
+	res := &ScanResult{
+		DisplayTargetFile: "package-lock.json",
+		Vulnerabilities: []ossIssue{
+			vulnerability("4.17.10", "app@1.0.0", "express@4.0.0", "lodash@4.17.10"),
+			vulnerability("4.17.10", "app@1.0.0", "lodash@4.17.10"),
+			vulnerability("4.17.10", "app@1.0.0", "express@4.0.0", "lodash@4.17.10"),
+			vulnerability("3.0.0", "app@1.0.0", "legacy@1.0.0", "lodash@3.0.0"),
+		},
+	}
+
+	issues := convertScanResultToIssues("/work/app", res, false)
+
+	require.Len(t, issues, 2)
+	assert.Equal(t, [][]string{{"express@4.0.0", "lodash@4.17.10"}, {"lodash@4.17.10"}}, issues[0].DependencyPaths)
+	require.NotNil(t, issues[0].IsTransitiveDependency)
+	assert.False(t, *issues[0].IsTransitiveDependency, "also a direct dependency")
+	assert.Equal(t, "3.0.0", issues[1].Version)
+	assert.Nil(t, issues[1].DependencyPaths, "single path is only in introducedThrough")
+	assert.NotEmpty(t, issues[0].FingerPrint)
+	assert.NotEqual(t, issues[0].FingerPrint, issues[1].FingerPrint)
+}
+
+func TestConvertScanResultToIssues_StableFingerprint(t *testing.T) {
+	pathA := ossIssue{Id: "SNYK-1", PackageName: "lodash", Version: "1.0.0", From: []string{"app@1.0.0", "a@1.0.0", "lodash@1.0.0"}}
+	pathB := ossIssue{Id: "SNYK-1", PackageName: "lodash", Version: "1.0.0", From: []string{"app@1.0.0", "b@1.0.0", "lodash@1.0.0"}}
+	scan := func(workDir string, vulnerabilities ...ossIssue) string {
+		issues := convertScanResultToIssues(workDir, &ScanResult{DisplayTargetFile: "package-lock.json", Vulnerabilities: vulnerabilities}, false)
+		require.Len(t, issues, 1)
+		return issues[0].FingerPrint
+	}
+
+	fingerprint := scan("/work/app", pathA, pathB)
+
+	assert.Equal(t, fingerprint, scan("/work/app", pathB, pathA), "path order does not matter")
+	assert.Equal(t, fingerprint, scan("/tmp/checkout/app", pathA, pathB), "scanned directory does not matter")
+	assert.NotEqual(t, fingerprint, scan("/work/app", pathA), "paths are part of the identity")
+
+	packageFingerprint := func(workDir string, vulnerabilities ...ossIssue) string {
+		issues := convertScanResultToIssues(workDir, &ScanResult{DisplayTargetFile: "package-lock.json", Vulnerabilities: vulnerabilities}, false)
+		require.Len(t, issues, 1)
+		return issues[0].PackageFingerPrint
+	}
+	assert.NotEmpty(t, packageFingerprint("/work/app", pathA))
+	assert.Equal(t, packageFingerprint("/work/app", pathA), packageFingerprint("/tmp/checkout/app", pathA, pathB), "paths are not part of the package identity")
+}
+
+func TestConvertOssJsonToIssuesAndProjects(t *testing.T) {
+	output := `[
+		{"ok": false, "projectName": "api", "packageManager": "npm", "displayTargetFile": "services/api/package-lock.json", "dependencyCount": 42,
+		 "vulnerabilities": [{"id": "SNYK-1", "packageName": "lodash", "version": "1.0.0", "severity": "high", "from": ["api@1.0.0", "lodash@1.0.0"]}]},
+		{"ok": true, "projectName": "tools", "packageManager": "gomodules", "displayTargetFile": "tools/go.mod", "dependencyCount": 7, "vulnerabilities": []},
+		{"ok": false, "error": "Could not find lockfile", "path": "legacy"}
+	]`
+
+	issues, projects, err := ConvertOssJsonToIssuesAndProjects("/work", []byte(output), false)
+	require.NoError(t, err)
+
+	require.Len(t, issues, 1)
+	assert.Equal(t, []Project{
+		{Name: "api", PackageManager: "npm", TargetFile: "services/api/package-lock.json", DependencyCount: 42, Ok: false, IssueCount: 1},
+		{Name: "tools", PackageManager: "gomodules", TargetFile: "tools/go.mod", DependencyCount: 7, Ok: true},
+		{Error: "Could not find lockfile", Path: "legacy"},
+	}, projects)
+
+	t.Run("counts are updated for a subset of issues", func(t *testing.T) {
+		CountProjectIssues("/work", projects, nil)
+		assert.Zero(t, projects[0].IssueCount)
+	})
+}
+
+func TestConvertOssJsonToIssues_LicenseIssues(t *testing.T) {
+	output := `{
+		"ok": false, "packageManager": "npm", "displayTargetFile": "package-lock.json",
+		"licensesPolicy": {"orgLicenseRules": {"GPL-3.0": {"licenseType": "GPL-3.0", "severity": "high", "instructions": "Ask legal before using GPL-3.0 dependencies"}}},
+		"vulnerabilities": [
+			{"id": "snyk:lic:npm:readline:GPL-3.0", "type": "license", "license": "GPL-3.0", "title": "GPL-3.0 license", "severity": "medium", "packageName": "readline", "version": "1.3.0", "from": ["app@1.0.0", "readline@1.3.0"]},
+			{"id": "snyk:lic:npm:left-pad:WTFPL", "type": "license", "license": "WTFPL", "title": "WTFPL license", "severity": "low", "packageName": "left-pad", "version": "1.0.0", "from": ["app@1.0.0", "left-pad@1.0.0"]},
+			{"id": "SNYK-JS-LODASH-1", "license": "MIT", "title": "Prototype Pollution", "severity": "high", "packageName": "lodash", "version": "4.17.15", "from": ["app@1.0.0", "lodash@4.17.15"]}
+		]
+	}`
+
+	issues, err := ConvertOssJsonToIssues("/work", []byte(output), false)
+	require.NoError(t, err)
+	require.Len(t, issues, 3)
+
+	assert.Equal(t, types.LicenseIssueType, issues[0].Type)
+	assert.Equal(t, "GPL-3.0", issues[0].License)
+	assert.Equal(t, "high", issues[0].Severity, "org policy severity")
+	assert.Equal(t, "Ask legal before using GPL-3.0 dependencies", issues[0].Remediation)
+	assert.Equal(t, "GPL-3.0 license in package readline@1.3.0. Ask legal before using GPL-3.0 dependencies", issues[0].Message)
+
+	assert.Equal(t, types.LicenseIssueType, issues[1].Type)
+	assert.Equal(t, "low", issues[1].Severity, "no policy rule keeps the reported severity")
+
+	assert.Empty(t, issues[2].Type, "vulnerabilities have no type")
+	assert.Empty(t, issues[2].License)
+}
+
+func TestParseIgnoreSettings(t *testing.T) {
+	t.Run("most restrictive settings of all projects", func(t *testing.T) {
+		output := `[
+			{"ok": true, "ignoreSettings": {"adminOnly": false, "reasonRequired": true, "disregardFilesystemIgnores": false}},
+			{"ok": true, "ignoreSettings": {"adminOnly": true, "reasonRequired": false, "disregardFilesystemIgnores": false}}
+		]`
+
+		settings, ok := ParseIgnoreSettings([]byte(output))
+
+		assert.True(t, ok)
+		assert.Equal(t, IgnoreSettings{AdminOnly: true, ReasonRequired: true}, settings)
+	})
+
+	t.Run("single project", func(t *testing.T) {
+		settings, ok := ParseIgnoreSettings([]byte(`{"ok": true, "ignoreSettings": {"disregardFilesystemIgnores": true}}`))
+
+		assert.True(t, ok)
+		assert.True(t, settings.DisregardFilesystemIgnores)
+	})
+
+	t.Run("no settings reported", func(t *testing.T) {
+		_, ok := ParseIgnoreSettings([]byte(`{"ok": false, "error": "Authentication failed"}`))
+		assert.False(t, ok)
+
+		_, ok = ParseIgnoreSettings([]byte("not json"))
+		assert.False(t, ok)
+	})
+}
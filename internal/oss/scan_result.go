@@ -14,7 +14,7 @@ type ScanResult struct {
 	IsPrivate         bool           `json:"isPrivate"`
 	LicensesPolicy    licensesPolicy `json:"licensesPolicy"`
 	PackageManager    string         `json:"packageManager"`
-	IgnoreSettings    ignoreSettings `json:"ignoreSettings"`
+	IgnoreSettings    IgnoreSettings `json:"ignoreSettings"`
 	Summary           string         `json:"summary"`
 	FilesystemPolicy  bool           `json:"filesystemPolicy"`
 	UniqueCount       int            `json:"uniqueCount"`
@@ -22,6 +22,7 @@ type ScanResult struct {
 	FoundProjectCount int            `json:"foundProjectCount"`
 	DisplayTargetFile string         `json:"displayTargetFile"`
 	Path              string         `json:"path"`
+	Error             string         `json:"error,omitempty"`
 	Remediation       remediation    `json:"remediation,omitempty"`
 	Filtered          struct {
 		Ignore []any `json:"ignore"`
@@ -91,7 +92,8 @@ type Insights struct {
 	TriageAdvice any `json:"triageAdvice"`
 }
 
-type ignoreSettings struct {
+// IgnoreSettings are the org settings that control who may ignore issues and how
+type IgnoreSettings struct {
 	AdminOnly                  bool `json:"adminOnly"`
 	ReasonRequired             bool `json:"reasonRequired"`
 	DisregardFilesystemIgnores bool `json:"disregardFilesystemIgnores"`
@@ -100,418 +102,14 @@ type ignoreSettings struct {
 type licensesPolicy struct {
 	Severities struct {
 	} `json:"severities"`
-	OrgLicenseRules struct {
-		GPL20 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"GPL-2.0"`
-		GPL30 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"GPL-3.0"`
-		LGPL20 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"LGPL-2.0"`
-		LGPL30 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"LGPL-3.0"`
-		EPL10 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"EPL-1.0"`
-		EPL20 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"EPL-2.0"`
-		CPOL102 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CPOL-1.02"`
-		MPL10 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"MPL-1.0"`
-		MPL11 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"MPL-1.1"`
-		MPL20 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"MPL-2.0"`
-		MPL20NoCopyleftException struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"MPL-2.0-no-copyleft-exception"`
-		AGPL30 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"AGPL-3.0"`
-		AGPL10 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"AGPL-1.0"`
-		MSRL struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"MS-RL"`
-		GPL20WithClasspathException struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"GPL-2.0-with-classpath-exception"`
-		APSL10 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"APSL-1.0"`
-		APSL11 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"APSL-1.1"`
-		APSL12 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"APSL-1.2"`
-		APSL20 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"APSL-2.0"`
-		CPAL10 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CPAL-1.0"`
-		EUPL10 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"EUPL-1.0"`
-		EUPL11 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"EUPL-1.1"`
-		OSL30 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"OSL-3.0"`
-		Artistic10 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"Artistic-1.0"`
-		Artistic10Perl struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"Artistic-1.0-Perl"`
-		Artistic10Cl8 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"Artistic-1.0-cl8"`
-		Artistic20 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"Artistic-2.0"`
-		RPSL10 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"RPSL-1.0"`
-		RPL11 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"RPL-1.1"`
-		RPL15 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"RPL-1.5"`
-		CCBYNC10 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CC-BY-NC-1.0"`
-		CCBYNC20 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CC-BY-NC-2.0"`
-		CCBYNC25 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CC-BY-NC-2.5"`
-		CCBYNC30 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CC-BY-NC-3.0"`
-		CCBYNC40 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CC-BY-NC-4.0"`
-		CCBYNCND10 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CC-BY-NC-ND-1.0"`
-		CCBYNCND20 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CC-BY-NC-ND-2.0"`
-		CCBYNCND25 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CC-BY-NC-ND-2.5"`
-		CCBYNCND30 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CC-BY-NC-ND-3.0"`
-		CCBYNCND40 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CC-BY-NC-ND-4.0"`
-		CCBYNCSA10 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CC-BY-NC-SA-1.0"`
-		CCBYNCSA20 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CC-BY-NC-SA-2.0"`
-		CCBYNCSA25 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CC-BY-NC-SA-2.5"`
-		CCBYNCSA30 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CC-BY-NC-SA-3.0"`
-		CCBYNCSA40 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CC-BY-NC-SA-4.0"`
-		CCBYND10 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CC-BY-ND-1.0"`
-		CCBYND20 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CC-BY-ND-2.0"`
-		CCBYND25 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CC-BY-ND-2.5"`
-		CCBYND30 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CC-BY-ND-3.0"`
-		CCBYND40 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CC-BY-ND-4.0"`
-		CCBYSA10 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CC-BY-SA-1.0"`
-		CCBYSA40 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CC-BY-SA-4.0"`
-		CCBYSA30 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CC-BY-SA-3.0"`
-		CCBYSA25 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CC-BY-SA-2.5"`
-		CCBYSA20 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CC-BY-SA-2.0"`
-		GPL10 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"GPL-1.0"`
-		LGPL21 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"LGPL-2.1"`
-		CDDL10 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CDDL-1.0"`
-		CDDL11 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CDDL-1.1"`
-		OSL10 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"OSL-1.0"`
-		OSL11 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"OSL-1.1"`
-		OSL20 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"OSL-2.0"`
-		OSL21 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"OSL-2.1"`
-		CPL10 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CPL-1.0"`
-		Sleepycat struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"Sleepycat"`
-		AFL11 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"AFL-1.1"`
-		AFL12 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"AFL-1.2"`
-		AFL20 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"AFL-2.0"`
-		AFL21 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"AFL-2.1"`
-		AFL30 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"AFL-3.0"`
-		OCLC20 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"OCLC-2.0"`
-		LGPLLR struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"LGPLLR"`
-		QPL10 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"QPL-1.0"`
-		SISSL struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"SISSL"`
-		SISSL12 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"SISSL-1.2"`
-		Watcom10 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"Watcom-1.0"`
-		CECILL10 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CECILL-1.0"`
-		CECILL11 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CECILL-1.1"`
-		CECILL20 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CECILL-2.0"`
-		CECILL21 struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CECILL-2.1"`
-		CECILLB struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CECILL-B"`
-		CECILLC struct {
-			LicenseType  string `json:"licenseType"`
-			Severity     string `json:"severity"`
-			Instructions string `json:"instructions"`
-		} `json:"CECILL-C"`
-	} `json:"orgLicenseRules"`
+	// OrgLicenseRules are the license policy of the org, keyed by license ID
+	OrgLicenseRules map[string]licenseRule `json:"orgLicenseRules"`
+}
+
+type licenseRule struct {
+	LicenseType  string `json:"licenseType"`
+	Severity     string `json:"severity"`
+	Instructions string `json:"instructions"`
 }
 
 type identifiers struct {
@@ -573,6 +171,10 @@ type ossIssue struct {
 	AppliedPolicyRules   AppliedPolicyRules `json:"appliedPolicyRules,omitempty"`
 	IsIgnored            bool               `json:"isIgnored,omitempty"`
 	Ignores              []projectIgnore    `json:"ignores,omitempty"`
+	// DockerfileInstruction is set by container scans with a Dockerfile
+	DockerfileInstruction string `json:"dockerfileInstruction,omitempty"`
+	// Type is "license" for license policy violations, vulnerabilities have no type
+	Type string `json:"type,omitempty"`
 }
 
 type AppliedPolicyRules struct {
@@ -0,0 +1,282 @@
+package policy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the policy file in the project directory
+const FileName = ".snyk"
+
+// Version is the policy schema version of new policy files
+const Version = "v1.25.0"
+
+// AllPaths is the path of ignore rules that apply wherever the issue is found
+const AllPaths = "*"
+
+const header = "Snyk (https://snyk.io) policy file, patches or ignores known vulnerabilities."
+
+// timeFormat is the timestamp format written by the CLI
+const timeFormat = "2006-01-02T15:04:05.000Z"
+
+// Ignore is an ignore rule of the policy. Path is the dependency path or resource path the rule applies to.
+type Ignore struct {
+	ID      string     `json:"id"`
+	Path    string     `json:"path"`
+	Reason  string     `json:"reason,omitempty"`
+	Expires *time.Time `json:"expires,omitempty"`
+	Created *time.Time `json:"created,omitempty"`
+}
+
+type rule struct {
+	Reason  string `yaml:"reason"`
+	Expires string `yaml:"expires"`
+	Created string `yaml:"created"`
+}
+
+// Policy is a .snyk policy file. It is edited as YAML node tree, so comments and
+// sections this package doesn't know, like patches and exclusions, are kept on save.
+type Policy struct {
+	path string
+	doc  *yaml.Node
+}
+
+// Load reads the policy file of a project directory. A new policy is returned if the file doesn't exist.
+func Load(dir string) (*Policy, error) {
+	path := filepath.Join(dir, FileName)
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return newPolicy(path), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if err = yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %w", path, err)
+	}
+	if doc.Kind == 0 {
+		return newPolicy(path), nil
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("invalid policy file %s: expected a mapping", path)
+	}
+	return &Policy{path: path, doc: &doc}, nil
+}
+
+func newPolicy(path string) *Policy {
+	root := mappingNode()
+	setValue(root, "version", stringNode(Version))
+	root.Content[0].HeadComment = header
+	setValue(root, "ignore", mappingNode())
+	setValue(root, "patch", mappingNode())
+	return &Policy{path: path, doc: &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}}}
+}
+
+// Path returns the path of the policy file
+func (p *Policy) Path() string {
+	return p.path
+}
+
+// Ignores returns the ignore rules in the order of the policy file
+func (p *Policy) Ignores() ([]Ignore, error) {
+	ignores := []Ignore{}
+	ignoreNode := p.ignoreNode(false)
+	if ignoreNode == nil {
+		return ignores, nil
+	}
+	for i := 0; i+1 < len(ignoreNode.Content); i += 2 {
+		id := ignoreNode.Content[i].Value
+		paths := ignoreNode.Content[i+1]
+		if paths.Kind != yaml.SequenceNode {
+			return nil, fmt.Errorf("invalid policy file %s: ignore rules of %s are not a list", p.path, id)
+		}
+		for _, entry := range paths.Content {
+			if entry.Kind != yaml.MappingNode {
+				return nil, fmt.Errorf("invalid policy file %s: invalid ignore rule of %s", p.path, id)
+			}
+			for j := 0; j+1 < len(entry.Content); j += 2 {
+				var r rule
+				if err := entry.Content[j+1].Decode(&r); err != nil {
+					return nil, fmt.Errorf("invalid policy file %s: invalid ignore rule of %s: %w", p.path, id, err)
+				}
+				ignores = append(ignores, Ignore{
+					ID:      id,
+					Path:    entry.Content[j].Value,
+					Reason:  r.Reason,
+					Expires: parseTime(r.Expires),
+					Created: parseTime(r.Created),
+				})
+			}
+		}
+	}
+	return ignores, nil
+}
+
+// AddIgnore adds an ignore rule. An existing rule of the same issue and path is replaced.
+func (p *Policy) AddIgnore(ignore Ignore) {
+	ignoreNode := p.ignoreNode(true)
+	paths := lookup(ignoreNode, ignore.ID)
+	if paths == nil || paths.Kind != yaml.SequenceNode {
+		paths = &yaml.Node{Kind: yaml.SequenceNode}
+		setValue(ignoreNode, ignore.ID, paths)
+	}
+	paths.Style = 0
+
+	value := ruleNode(ignore)
+	for _, entry := range paths.Content {
+		if existing := lookup(entry, ignore.Path); existing != nil {
+			*existing = *value
+			return
+		}
+	}
+	entry := mappingNode()
+	setValue(entry, ignore.Path, value)
+	paths.Content = append(paths.Content, entry)
+}
+
+// RemoveIgnore removes the ignore rules of an issue for the given path, or for all paths if path is empty.
+// It returns the number of removed rules.
+func (p *Policy) RemoveIgnore(id string, path string) int {
+	ignoreNode := p.ignoreNode(false)
+	if ignoreNode == nil {
+		return 0
+	}
+	for i := 0; i+1 < len(ignoreNode.Content); i += 2 {
+		if ignoreNode.Content[i].Value != id {
+			continue
+		}
+		paths := ignoreNode.Content[i+1]
+		removed := 0
+		var kept []*yaml.Node
+		for _, entry := range paths.Content {
+			if entry.Kind != yaml.MappingNode {
+				kept = append(kept, entry)
+				continue
+			}
+			var keptPairs []*yaml.Node
+			for j := 0; j+1 < len(entry.Content); j += 2 {
+				if path == "" || entry.Content[j].Value == path {
+					removed++
+					continue
+				}
+				keptPairs = append(keptPairs, entry.Content[j], entry.Content[j+1])
+			}
+			if len(keptPairs) > 0 {
+				entry.Content = keptPairs
+				kept = append(kept, entry)
+			}
+		}
+		paths.Content = kept
+		if len(kept) == 0 {
+			ignoreNode.Content = append(ignoreNode.Content[:i], ignoreNode.Content[i+2:]...)
+		}
+		return removed
+	}
+	return 0
+}
+
+// Save writes the policy file
+func (p *Policy) Save() error {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(p.doc); err != nil {
+		return err
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+
+	perm := fs.FileMode(0644)
+	if info, err := os.Stat(p.path); err == nil {
+		perm = info.Mode().Perm()
+	}
+	return os.WriteFile(p.path, buf.Bytes(), perm)
+}
+
+// ignoreNode returns the mapping of issue IDs to ignore rules. If create is set, a missing or empty mapping is added.
+func (p *Policy) ignoreNode(create bool) *yaml.Node {
+	root := p.doc.Content[0]
+	node := lookup(root, "ignore")
+	if node != nil && node.Kind == yaml.MappingNode {
+		if create {
+			node.Style = 0
+		}
+		return node
+	}
+	if !create {
+		return nil
+	}
+	if node == nil {
+		node = mappingNode()
+		setValue(root, "ignore", node)
+		return node
+	}
+	// `ignore:` without rules is null
+	*node = *mappingNode()
+	return node
+}
+
+func ruleNode(ignore Ignore) *yaml.Node {
+	node := mappingNode()
+	setValue(node, "reason", stringNode(ignore.Reason))
+	if ignore.Expires != nil {
+		setValue(node, "expires", timeNode(*ignore.Expires))
+	}
+	if ignore.Created != nil {
+		setValue(node, "created", timeNode(*ignore.Created))
+	}
+	return node
+}
+
+func lookup(mapping *yaml.Node, key string) *yaml.Node {
+	if mapping.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func setValue(mapping *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+	mapping.Content = append(mapping.Content, stringNode(key), value)
+}
+
+func mappingNode() *yaml.Node {
+	return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+}
+
+func stringNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+func timeNode(t time.Time) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!timestamp", Value: t.UTC().Format(timeFormat)}
+}
+
+func parseTime(value string) *time.Time {
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
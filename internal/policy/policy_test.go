@@ -0,0 +1,177 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const existingPolicy = `# Snyk (https://snyk.io) policy file, patches or ignores known vulnerabilities.
+version: v1.25.0
+# accepted until the next major release
+ignore:
+  SNYK-JS-MINIMIST-559764:
+    - 'mkdirp > minimist':
+        reason: not reachable
+        expires: 2030-01-01T00:00:00.000Z
+        created: 2024-05-02T10:00:00.000Z
+patch: {}
+exclude:
+  global:
+    - test/**
+`
+
+func writePolicy(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, FileName), []byte(content), 0600))
+	return dir
+}
+
+func readPolicy(t *testing.T, dir string) string {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join(dir, FileName))
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("existing policy", func(t *testing.T) {
+		dir := writePolicy(t, existingPolicy)
+
+		p, err := Load(dir)
+		require.NoError(t, err)
+		ignores, err := p.Ignores()
+		require.NoError(t, err)
+
+		require.Len(t, ignores, 1)
+		assert.Equal(t, "SNYK-JS-MINIMIST-559764", ignores[0].ID)
+		assert.Equal(t, "mkdirp > minimist", ignores[0].Path)
+		assert.Equal(t, "not reachable", ignores[0].Reason)
+		require.NotNil(t, ignores[0].Expires)
+		assert.Equal(t, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), *ignores[0].Expires)
+		assert.Equal(t, filepath.Join(dir, FileName), p.Path())
+	})
+
+	t.Run("missing policy", func(t *testing.T) {
+		p, err := Load(t.TempDir())
+		require.NoError(t, err)
+
+		ignores, err := p.Ignores()
+		require.NoError(t, err)
+		assert.Empty(t, ignores)
+	})
+
+	t.Run("invalid policy", func(t *testing.T) {
+		_, err := Load(writePolicy(t, "- not\n- a mapping\n"))
+		assert.ErrorContains(t, err, "invalid policy file")
+	})
+}
+
+func TestAddIgnore(t *testing.T) {
+	expires := time.Date(2031, 2, 3, 0, 0, 0, 0, time.UTC)
+	created := time.Date(2026, 1, 2, 8, 30, 0, 0, time.UTC)
+
+	t.Run("keeps the rest of the policy", func(t *testing.T) {
+		dir := writePolicy(t, existingPolicy)
+		p, err := Load(dir)
+		require.NoError(t, err)
+
+		p.AddIgnore(Ignore{ID: "SNYK-JS-LODASH-567746", Path: AllPaths, Reason: "fix pending: upstream", Expires: &expires, Created: &created})
+		require.NoError(t, p.Save())
+
+		content := readPolicy(t, dir)
+		assert.Contains(t, content, "# accepted until the next major release\n")
+		assert.Contains(t, content, "exclude:\n  global:\n    - test/**\n")
+		assert.Contains(t, content, `  SNYK-JS-LODASH-567746:
+    - '*':
+        reason: 'fix pending: upstream'
+        expires: 2031-02-03T00:00:00.000Z
+        created: 2026-01-02T08:30:00.000Z
+`)
+
+		reloaded, err := Load(dir)
+		require.NoError(t, err)
+		ignores, err := reloaded.Ignores()
+		require.NoError(t, err)
+		require.Len(t, ignores, 2)
+		assert.Equal(t, "fix pending: upstream", ignores[1].Reason)
+	})
+
+	t.Run("replaces the rule of the same path", func(t *testing.T) {
+		p, err := Load(writePolicy(t, existingPolicy))
+		require.NoError(t, err)
+
+		p.AddIgnore(Ignore{ID: "SNYK-JS-MINIMIST-559764", Path: "mkdirp > minimist", Reason: "still not reachable", Expires: &expires})
+
+		ignores, err := p.Ignores()
+		require.NoError(t, err)
+		require.Len(t, ignores, 1)
+		assert.Equal(t, "still not reachable", ignores[0].Reason)
+		assert.Equal(t, expires, *ignores[0].Expires)
+	})
+
+	t.Run("new policy", func(t *testing.T) {
+		dir := t.TempDir()
+		p, err := Load(dir)
+		require.NoError(t, err)
+
+		p.AddIgnore(Ignore{ID: "SNYK-CC-TF-1", Path: AllPaths, Reason: "accepted", Expires: &expires})
+		require.NoError(t, p.Save())
+
+		content := readPolicy(t, dir)
+		assert.Contains(t, content, "version: "+Version)
+		assert.Contains(t, content, "SNYK-CC-TF-1:")
+	})
+
+	t.Run("empty ignore section", func(t *testing.T) {
+		p, err := Load(writePolicy(t, "version: v1.25.0\nignore:\n"))
+		require.NoError(t, err)
+
+		p.AddIgnore(Ignore{ID: "SNYK-1", Path: AllPaths, Reason: "accepted", Expires: &expires})
+
+		ignores, err := p.Ignores()
+		require.NoError(t, err)
+		assert.Len(t, ignores, 1)
+	})
+}
+
+func TestRemoveIgnore(t *testing.T) {
+	expires := time.Date(2031, 2, 3, 0, 0, 0, 0, time.UTC)
+	load := func(t *testing.T) *Policy {
+		t.Helper()
+		p, err := Load(writePolicy(t, existingPolicy))
+		require.NoError(t, err)
+		p.AddIgnore(Ignore{ID: "SNYK-JS-MINIMIST-559764", Path: AllPaths, Reason: "accepted", Expires: &expires})
+		return p
+	}
+
+	t.Run("single path", func(t *testing.T) {
+		p := load(t)
+
+		assert.Equal(t, 1, p.RemoveIgnore("SNYK-JS-MINIMIST-559764", AllPaths))
+
+		ignores, err := p.Ignores()
+		require.NoError(t, err)
+		require.Len(t, ignores, 1)
+		assert.Equal(t, "mkdirp > minimist", ignores[0].Path)
+	})
+
+	t.Run("all paths", func(t *testing.T) {
+		p := load(t)
+
+		assert.Equal(t, 2, p.RemoveIgnore("SNYK-JS-MINIMIST-559764", ""))
+
+		ignores, err := p.Ignores()
+		require.NoError(t, err)
+		assert.Empty(t, ignores)
+	})
+
+	t.Run("unknown issue", func(t *testing.T) {
+		assert.Zero(t, load(t).RemoveIgnore("SNYK-JS-OTHER-1", ""))
+	})
+}
@@ -0,0 +1,154 @@
+package sarif
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/snyk/studio-mcp/internal/types"
+)
+
+// SourceRoot is the uriBaseId of locations relative to the scanned directory
+const SourceRoot = "%SRCROOT%"
+
+const informationURI = "https://snyk.io"
+
+var severityLevels = map[string]string{
+	"critical": "error",
+	"high":     "error",
+	"medium":   "warning",
+	"low":      "note",
+}
+
+// securitySeverities are the scores code scanning dashboards expect for each severity
+var securitySeverities = map[string]string{
+	"critical": "9.5",
+	"high":     "8.0",
+	"medium":   "5.5",
+	"low":      "3.0",
+}
+
+// ConvertIssuesToSarif converts issues of any scanner to a SARIF 2.1.0 log with a single run.
+// Locations below workDir are relative to SourceRoot, other locations are file URIs.
+func ConvertIssuesToSarif(driverName string, workDir string, issues []types.IssueData) Log {
+	run := Run{
+		Tool:    Tool{Driver: Driver{Name: driverName, InformationURI: informationURI, Rules: []Rule{}}},
+		Results: []Result{},
+	}
+	ruleIndexes := map[string]int{}
+	for _, issue := range issues {
+		ruleIndex, ok := ruleIndexes[issue.ID]
+		if !ok {
+			ruleIndex = len(run.Tool.Driver.Rules)
+			ruleIndexes[issue.ID] = ruleIndex
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, toRule(issue))
+		}
+		run.Results = append(run.Results, toResult(issue, ruleIndex, workDir))
+	}
+	return Log{Schema: Schema, Version: Version, Runs: []Run{run}}
+}
+
+func level(severity string) string {
+	if level, ok := severityLevels[strings.ToLower(severity)]; ok {
+		return level
+	}
+	return "warning"
+}
+
+func toRule(issue types.IssueData) Rule {
+	tag := "security"
+	if issue.Type == types.LicenseIssueType {
+		tag = "license"
+	}
+	return Rule{
+		ID:                   issue.ID,
+		Name:                 issue.Title,
+		ShortDescription:     Message{Text: issue.Title},
+		DefaultConfiguration: DefaultConfiguration{Level: level(issue.Severity)},
+		Properties: RuleProperties{
+			Tags:             []string{tag},
+			SecuritySeverity: securitySeverities[strings.ToLower(issue.Severity)],
+			CWE:              issue.CWEs,
+		},
+	}
+}
+
+func toResult(issue types.IssueData, ruleIndex int, workDir string) Result {
+	result := Result{
+		RuleID:    issue.ID,
+		RuleIndex: ruleIndex,
+		Level:     level(issue.Severity),
+		Message:   Message{Text: resultMessage(issue)},
+		Properties: ResultProperties{
+			Severity:          strings.ToLower(issue.Severity),
+			PackageName:       issue.PackageName,
+			Version:           issue.Version,
+			FixedIn:           issue.FixedIn,
+			CVEs:              issue.CVEs,
+			IntroducedThrough: issue.IntroducedThrough,
+			ResourcePath:      issue.ResourcePath,
+			Remediation:       issue.Remediation,
+			License:           issue.License,
+		},
+	}
+	if issue.FilePath != "" {
+		location := toLocation(workDir, issue.FilePath)
+		if issue.Line > 0 {
+			location.PhysicalLocation.Region = &Region{StartLine: issue.Line, StartColumn: issue.Column}
+		}
+		result.Locations = []Location{location}
+	}
+	if issue.FingerPrint != "" {
+		result.Fingerprints = map[string]string{"snyk/issue/v1": issue.FingerPrint}
+	}
+	if len(issue.Dataflow) > 0 {
+		threadFlow := ThreadFlow{Locations: []ThreadFlowLocation{}}
+		for _, element := range issue.Dataflow {
+			location := toLocation(workDir, string(element.FilePath))
+			// data flow ranges are 0-based, SARIF regions are 1-based
+			location.PhysicalLocation.Region = &Region{
+				StartLine:   element.FlowRange.Start.Line + 1,
+				StartColumn: element.FlowRange.Start.Character + 1,
+				EndLine:     element.FlowRange.End.Line + 1,
+				EndColumn:   element.FlowRange.End.Character,
+			}
+			threadFlow.Locations = append(threadFlow.Locations, ThreadFlowLocation{Location: location})
+		}
+		result.CodeFlows = []CodeFlow{{ThreadFlows: []ThreadFlow{threadFlow}}}
+	}
+	if issue.IsIgnored {
+		result.Suppressions = []Suppression{{Kind: "external", Status: "accepted"}}
+	}
+	return result
+}
+
+func resultMessage(issue types.IssueData) string {
+	if issue.Message != "" {
+		return issue.Message
+	}
+	if issue.PackageName != "" && issue.Version != "" {
+		return fmt.Sprintf("%s in %s@%s", issue.Title, issue.PackageName, issue.Version)
+	}
+	if issue.Title != "" {
+		return issue.Title
+	}
+	return issue.ID
+}
+
+func toLocation(workDir string, filePath string) Location {
+	artifact := ArtifactLocation{URI: filepath.ToSlash(filePath)}
+	if !filepath.IsAbs(filePath) {
+		artifact.URIBaseID = SourceRoot
+	} else if relPath, err := filepath.Rel(workDir, filePath); workDir != "" && err == nil && relPath != ".." && !strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		artifact = ArtifactLocation{URI: filepath.ToSlash(relPath), URIBaseID: SourceRoot}
+	} else {
+		uriPath := filepath.ToSlash(filePath)
+		// Windows paths start with the drive letter
+		if !strings.HasPrefix(uriPath, "/") {
+			uriPath = "/" + uriPath
+		}
+		artifact.URI = (&url.URL{Scheme: "file", Path: uriPath}).String()
+	}
+	return Location{PhysicalLocation: PhysicalLocation{ArtifactLocation: artifact}}
+}
@@ -0,0 +1,108 @@
+package sarif
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/studio-mcp/internal/types"
+)
+
+func TestConvertIssuesToSarif(t *testing.T) {
+	workDir := filepath.Join(t.TempDir(), "project")
+	issues := []types.IssueData{
+		{
+			ID:          "javascript/XSS",
+			Title:       "Cross-site Scripting",
+			Severity:    "high",
+			Message:     "Unsanitized input flows into the response",
+			FilePath:    filepath.Join(workDir, "src", "app.js"),
+			Line:        12,
+			Column:      5,
+			CWEs:        []string{"CWE-79"},
+			FingerPrint: "abc123",
+			IsIgnored:   true,
+			Dataflow: []types.DataflowElement{
+				{FilePath: types.FilePath(filepath.Join(workDir, "src", "app.js")), FlowRange: types.Range{Start: types.Position{Line: 9, Character: 2}, End: types.Position{Line: 9, Character: 10}}},
+			},
+		},
+		{
+			ID:          "SNYK-JS-LODASH-1",
+			Title:       "Prototype Pollution",
+			Severity:    "critical",
+			PackageName: "lodash",
+			Version:     "4.17.15",
+			FixedIn:     []string{"4.17.19"},
+			FilePath:    filepath.Join(workDir, "package.json"),
+		},
+		{
+			ID:          "SNYK-JS-LODASH-1",
+			Title:       "Prototype Pollution",
+			Severity:    "critical",
+			PackageName: "lodash",
+			Version:     "4.17.15",
+			FilePath:    filepath.Join(workDir, "web", "package.json"),
+		},
+	}
+
+	log := ConvertIssuesToSarif("Snyk Code", workDir, issues)
+
+	assert.Equal(t, Version, log.Version)
+	require.Len(t, log.Runs, 1)
+	run := log.Runs[0]
+	assert.Equal(t, "Snyk Code", run.Tool.Driver.Name)
+
+	t.Run("rules are shared by results of the same issue", func(t *testing.T) {
+		require.Len(t, run.Tool.Driver.Rules, 2)
+		assert.Equal(t, "SNYK-JS-LODASH-1", run.Tool.Driver.Rules[1].ID)
+		assert.Equal(t, "9.5", run.Tool.Driver.Rules[1].Properties.SecuritySeverity)
+		assert.Equal(t, []string{"CWE-79"}, run.Tool.Driver.Rules[0].Properties.CWE)
+		require.Len(t, run.Results, 3)
+		assert.Equal(t, 1, run.Results[1].RuleIndex)
+		assert.Equal(t, 1, run.Results[2].RuleIndex)
+	})
+
+	t.Run("code result", func(t *testing.T) {
+		result := run.Results[0]
+		assert.Equal(t, "error", result.Level)
+		assert.Equal(t, "Unsanitized input flows into the response", result.Message.Text)
+		require.Len(t, result.Locations, 1)
+		assert.Equal(t, ArtifactLocation{URI: "src/app.js", URIBaseID: SourceRoot}, result.Locations[0].PhysicalLocation.ArtifactLocation)
+		assert.Equal(t, &Region{StartLine: 12, StartColumn: 5}, result.Locations[0].PhysicalLocation.Region)
+		assert.Equal(t, map[string]string{"snyk/issue/v1": "abc123"}, result.Fingerprints)
+		assert.Equal(t, []Suppression{{Kind: "external", Status: "accepted"}}, result.Suppressions)
+		require.Len(t, result.CodeFlows, 1)
+		assert.Equal(t, &Region{StartLine: 10, StartColumn: 3, EndLine: 10, EndColumn: 10}, result.CodeFlows[0].ThreadFlows[0].Locations[0].Location.PhysicalLocation.Region)
+	})
+
+	t.Run("sca result", func(t *testing.T) {
+		result := run.Results[1]
+		assert.Equal(t, "Prototype Pollution in lodash@4.17.15", result.Message.Text)
+		assert.Equal(t, "package.json", result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		assert.Nil(t, result.Locations[0].PhysicalLocation.Region)
+		assert.Equal(t, []string{"4.17.19"}, result.Properties.FixedIn)
+		assert.Empty(t, result.Suppressions)
+	})
+
+	t.Run("serializes as SARIF", func(t *testing.T) {
+		content, err := json.Marshal(log)
+		require.NoError(t, err)
+
+		var document map[string]any
+		require.NoError(t, json.Unmarshal(content, &document))
+		assert.Equal(t, Schema, document["$schema"])
+		assert.Equal(t, "2.1.0", document["version"])
+	})
+}
+
+func TestToLocationOutsideWorkDir(t *testing.T) {
+	outside := filepath.Join(t.TempDir(), "other", "file.tf")
+
+	location := toLocation(filepath.Join(t.TempDir(), "project"), outside)
+
+	assert.Empty(t, location.PhysicalLocation.ArtifactLocation.URIBaseID)
+	assert.Contains(t, location.PhysicalLocation.ArtifactLocation.URI, "file://")
+}
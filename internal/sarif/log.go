@@ -0,0 +1,113 @@
+package sarif
+
+const (
+	Version = "2.1.0"
+	Schema  = "https://docs.oasis-open.org/sarif/sarif/v2.1.0/errata01/os/schemas/sarif-schema-2.1.0.json"
+)
+
+// Log is a SARIF 2.1.0 log with the properties needed to report Snyk issues
+type Log struct {
+	Schema  string `json:"$schema"`
+	Version string `json:"version"`
+	Runs    []Run  `json:"runs"`
+}
+
+type Run struct {
+	Tool    Tool     `json:"tool"`
+	Results []Result `json:"results"`
+}
+
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+type Driver struct {
+	Name           string `json:"name"`
+	InformationURI string `json:"informationUri,omitempty"`
+	Rules          []Rule `json:"rules"`
+}
+
+type Rule struct {
+	ID                   string               `json:"id"`
+	Name                 string               `json:"name,omitempty"`
+	ShortDescription     Message              `json:"shortDescription"`
+	DefaultConfiguration DefaultConfiguration `json:"defaultConfiguration"`
+	Properties           RuleProperties       `json:"properties"`
+}
+
+type DefaultConfiguration struct {
+	Level string `json:"level"`
+}
+
+type RuleProperties struct {
+	Tags []string `json:"tags,omitempty"`
+	// SecuritySeverity is the CVSS-like score that code scanning dashboards use to rank results
+	SecuritySeverity string   `json:"security-severity,omitempty"`
+	CWE              []string `json:"cwe,omitempty"`
+}
+
+type Message struct {
+	Text string `json:"text"`
+}
+
+type Result struct {
+	RuleID       string            `json:"ruleId"`
+	RuleIndex    int               `json:"ruleIndex"`
+	Level        string            `json:"level"`
+	Message      Message           `json:"message"`
+	Locations    []Location        `json:"locations,omitempty"`
+	Fingerprints map[string]string `json:"fingerprints,omitempty"`
+	CodeFlows    []CodeFlow        `json:"codeFlows,omitempty"`
+	Suppressions []Suppression     `json:"suppressions,omitempty"`
+	Properties   ResultProperties  `json:"properties"`
+}
+
+type ResultProperties struct {
+	Severity          string   `json:"severity"`
+	PackageName       string   `json:"packageName,omitempty"`
+	Version           string   `json:"version,omitempty"`
+	FixedIn           []string `json:"fixedIn,omitempty"`
+	CVEs              []string `json:"cves,omitempty"`
+	IntroducedThrough []string `json:"introducedThrough,omitempty"`
+	ResourcePath      string   `json:"resourcePath,omitempty"`
+	Remediation       string   `json:"remediation,omitempty"`
+	License           string   `json:"license,omitempty"`
+}
+
+type Location struct {
+	PhysicalLocation PhysicalLocation `json:"physicalLocation"`
+}
+
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+	Region           *Region          `json:"region,omitempty"`
+}
+
+type ArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type Region struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+type CodeFlow struct {
+	ThreadFlows []ThreadFlow `json:"threadFlows"`
+}
+
+type ThreadFlow struct {
+	Locations []ThreadFlowLocation `json:"locations"`
+}
+
+type ThreadFlowLocation struct {
+	Location Location `json:"location"`
+}
+
+type Suppression struct {
+	Kind   string `json:"kind"`
+	Status string `json:"status,omitempty"`
+}
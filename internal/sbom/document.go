@@ -0,0 +1,70 @@
+package sbom
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// DefaultFormat is the SBOM format generated if none is requested
+const DefaultFormat = "cyclonedx1.6+json"
+
+// Formats are the SBOM formats supported by `snyk sbom --format`
+var Formats = []string{
+	"cyclonedx1.4+json",
+	"cyclonedx1.4+xml",
+	"cyclonedx1.5+json",
+	"cyclonedx1.5+xml",
+	"cyclonedx1.6+json",
+	"cyclonedx1.6+xml",
+	"spdx2.3+json",
+}
+
+// FileExtension returns the conventional file extension of SBOM documents in the format
+func FileExtension(format string) string {
+	switch {
+	case strings.HasPrefix(format, "spdx"):
+		return "spdx.json"
+	case strings.HasSuffix(format, "+xml"):
+		return "cdx.xml"
+	default:
+		return "cdx.json"
+	}
+}
+
+// CountComponents validates the SBOM document and returns the number of its top-level components,
+// or packages in SPDX documents
+func CountComponents(format string, document []byte) (int, error) {
+	switch {
+	case strings.HasPrefix(format, "spdx"):
+		var spdx struct {
+			SPDXVersion string            `json:"spdxVersion"`
+			Packages    []json.RawMessage `json:"packages"`
+		}
+		if err := json.Unmarshal(document, &spdx); err != nil || spdx.SPDXVersion == "" {
+			return 0, fmt.Errorf("not an SPDX document")
+		}
+		return len(spdx.Packages), nil
+	case strings.HasSuffix(format, "+xml"):
+		var cyclonedx struct {
+			XMLName    xml.Name `xml:"bom"`
+			Components []struct {
+				Name string `xml:"name"`
+			} `xml:"components>component"`
+		}
+		if err := xml.Unmarshal(document, &cyclonedx); err != nil {
+			return 0, fmt.Errorf("not a CycloneDX XML document")
+		}
+		return len(cyclonedx.Components), nil
+	default:
+		var cyclonedx struct {
+			BOMFormat  string            `json:"bomFormat"`
+			Components []json.RawMessage `json:"components"`
+		}
+		if err := json.Unmarshal(document, &cyclonedx); err != nil || cyclonedx.BOMFormat != "CycloneDX" {
+			return 0, fmt.Errorf("not a CycloneDX JSON document")
+		}
+		return len(cyclonedx.Components), nil
+	}
+}
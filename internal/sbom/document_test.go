@@ -0,0 +1,57 @@
+package sbom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileExtension(t *testing.T) {
+	assert.Equal(t, "cdx.json", FileExtension("cyclonedx1.6+json"))
+	assert.Equal(t, "cdx.xml", FileExtension("cyclonedx1.4+xml"))
+	assert.Equal(t, "spdx.json", FileExtension("spdx2.3+json"))
+}
+
+func TestCountComponents(t *testing.T) {
+	testCases := []struct {
+		name     string
+		format   string
+		document string
+		expected int
+	}{
+		{
+			name:     "CycloneDX JSON",
+			format:   "cyclonedx1.5+json",
+			document: `{"bomFormat":"CycloneDX","specVersion":"1.5","components":[{"name":"express"},{"name":"qs"}]}`,
+			expected: 2,
+		},
+		{
+			name:     "CycloneDX XML counts top-level components",
+			format:   "cyclonedx1.6+xml",
+			document: `<?xml version="1.0" encoding="UTF-8"?><bom xmlns="http://cyclonedx.org/schema/bom/1.6" version="1"><components><component type="library"><name>express</name><components><component type="library"><name>nested</name></component></components></component><component type="library"><name>qs</name></component></components></bom>`,
+			expected: 2,
+		},
+		{
+			name:     "SPDX packages",
+			format:   "spdx2.3+json",
+			document: `{"spdxVersion":"SPDX-2.3","packages":[{"name":"app"},{"name":"express"},{"name":"qs"}]}`,
+			expected: 3,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			count, err := CountComponents(tc.format, []byte(tc.document))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, count)
+		})
+	}
+
+	t.Run("not a document", func(t *testing.T) {
+		_, err := CountComponents("cyclonedx1.6+json", []byte(`{"ok":false,"error":"Unsupported format"}`))
+		assert.ErrorContains(t, err, "not a CycloneDX JSON document")
+
+		_, err = CountComponents("cyclonedx1.6+xml", []byte("Authentication failed"))
+		assert.ErrorContains(t, err, "not a CycloneDX XML document")
+	})
+}
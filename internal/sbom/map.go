@@ -0,0 +1,177 @@
+package sbom
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/snyk/studio-mcp/internal/oss"
+	"github.com/snyk/studio-mcp/internal/types"
+)
+
+// purlTypes maps Snyk package managers to package URL types, see https://github.com/package-url/purl-spec
+var purlTypes = map[string]string{
+	"npm":       "npm",
+	"yarn":      "npm",
+	"pnpm":      "npm",
+	"maven":     "maven",
+	"gradle":    "maven",
+	"sbt":       "maven",
+	"pip":       "pypi",
+	"pipenv":    "pypi",
+	"poetry":    "pypi",
+	"pypi":      "pypi",
+	"golang":    "golang",
+	"gomodules": "golang",
+	"golangdep": "golang",
+	"rubygems":  "gem",
+	"nuget":     "nuget",
+	"paket":     "nuget",
+	"composer":  "composer",
+	"cargo":     "cargo",
+	"cocoapods": "cocoapods",
+	"swift":     "swift",
+	"hex":       "hex",
+	"pub":       "pub",
+	"deb":       "deb",
+	"apk":       "apk",
+	"rpm":       "rpm",
+	"conan":     "conan",
+}
+
+// ConvertSbomJsonToIssues converts the output of `snyk sbom test --json` into issues, and groups them
+// per SBOM component. Components are ordered by their highest severity and issue count.
+func ConvertSbomJsonToIssues(workDir string, res []byte, includeIgnores bool) ([]types.IssueData, []Component, error) {
+	output := strings.TrimSpace(string(res))
+	var ossResults []oss.ScanResult
+	var sbomResults []ScanResult
+	if strings.HasPrefix(output, "[") {
+		if err := errors.Join(json.Unmarshal(res, &ossResults), json.Unmarshal(res, &sbomResults)); err != nil {
+			return nil, nil, errors.Join(err, fmt.Errorf("couldn't unmarshal CLI response. Input: %s", output))
+		}
+	} else {
+		var ossResult oss.ScanResult
+		var sbomResult ScanResult
+		if err := errors.Join(json.Unmarshal(res, &ossResult), json.Unmarshal(res, &sbomResult)); err != nil {
+			return nil, nil, errors.Join(err, fmt.Errorf("couldn't unmarshal CLI response. Input: %s", output))
+		}
+		ossResults = append(ossResults, ossResult)
+		sbomResults = append(sbomResults, sbomResult)
+	}
+
+	purls := reportedPurls(sbomResults)
+	issues := oss.ConvertToIssue(workDir, ossResults, includeIgnores)
+	if issues == nil {
+		issues = []types.IssueData{}
+	}
+	for i := range issues {
+		issues[i].Purl = purls[componentKey(issues[i])]
+		if issues[i].Purl == "" {
+			issues[i].Purl = buildPurl(issues[i].Ecosystem, issues[i].PackageName, issues[i].Version)
+		}
+	}
+	return issues, groupByComponent(issues), nil
+}
+
+// reportedPurls collects the package URLs the CLI reported for the vulnerable components
+func reportedPurls(results []ScanResult) map[string]string {
+	purls := map[string]string{}
+	for _, result := range results {
+		for _, vuln := range result.Vulnerabilities {
+			purl := vuln.Purl
+			if purl == "" {
+				purl = vuln.PackageURL
+			}
+			if purl != "" {
+				purls[vuln.PackageName+"@"+vuln.Version] = purl
+			}
+		}
+	}
+	return purls
+}
+
+func componentKey(issue types.IssueData) string {
+	return issue.PackageName + "@" + issue.Version
+}
+
+// buildPurl builds the package URL of a component from its ecosystem, name and version
+func buildPurl(ecosystem string, name string, version string) string {
+	purlType, ok := purlTypes[strings.ToLower(ecosystem)]
+	if !ok || name == "" {
+		return ""
+	}
+
+	var segments []string
+	switch purlType {
+	case "maven":
+		// maven packages are named groupId:artifactId
+		segments = strings.SplitN(name, ":", 2)
+	case "deb", "apk", "rpm":
+		// OS packages are named source/binary, the binary package is the component
+		if i := strings.LastIndex(name, "/"); i >= 0 {
+			name = name[i+1:]
+		}
+		segments = []string{name}
+	default:
+		segments = strings.Split(name, "/")
+	}
+	for i, segment := range segments {
+		// the @ of npm scopes must be encoded, as it separates the version
+		segments[i] = strings.ReplaceAll(url.PathEscape(segment), "@", "%40")
+	}
+
+	purl := "pkg:" + purlType + "/" + strings.Join(segments, "/")
+	if version != "" {
+		purl += "@" + url.PathEscape(version)
+	}
+	return purl
+}
+
+func groupByComponent(issues []types.IssueData) []Component {
+	components := []Component{}
+	indexByKey := map[string]int{}
+	for _, issue := range issues {
+		key := componentKey(issue)
+		index, exists := indexByKey[key]
+		if !exists {
+			index = len(components)
+			indexByKey[key] = index
+			components = append(components, Component{
+				Name:            issue.PackageName,
+				Version:         issue.Version,
+				Ecosystem:       issue.Ecosystem,
+				Purl:            issue.Purl,
+				HighestSeverity: issue.Severity,
+				IssueIDs:        []string{},
+			})
+		}
+		component := &components[index]
+		if slices.Contains(component.IssueIDs, issue.ID) {
+			continue
+		}
+		component.IssueIDs = append(component.IssueIDs, issue.ID)
+		component.IssueCount++
+		if types.SeverityRank(issue.Severity) < types.SeverityRank(component.HighestSeverity) {
+			component.HighestSeverity = issue.Severity
+		}
+		for _, fixedIn := range issue.FixedIn {
+			if !slices.Contains(component.FixedIn, fixedIn) {
+				component.FixedIn = append(component.FixedIn, fixedIn)
+			}
+		}
+		if component.Remediation == "" {
+			component.Remediation = issue.Remediation
+		}
+	}
+
+	slices.SortStableFunc(components, func(a, b Component) int {
+		if rankDiff := types.SeverityRank(a.HighestSeverity) - types.SeverityRank(b.HighestSeverity); rankDiff != 0 {
+			return rankDiff
+		}
+		return b.IssueCount - a.IssueCount
+	})
+	return components
+}
@@ -0,0 +1,97 @@
+package sbom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sbomTestOutput = `{
+	"ok": false,
+	"packageManager": "npm",
+	"vulnerabilities": [
+		{"id": "SNYK-JS-LODASH-1", "title": "Prototype Pollution", "severity": "high", "packageName": "lodash", "version": "4.17.15", "fixedIn": ["4.17.19"], "from": ["sbom@1.0.0", "lodash@4.17.15"], "upgradePath": [false, "lodash@4.17.19"], "isUpgradable": true, "packageManager": "npm"},
+		{"id": "SNYK-JS-LODASH-2", "title": "Command Injection", "severity": "critical", "packageName": "lodash", "version": "4.17.15", "fixedIn": ["4.17.21"], "from": ["sbom@1.0.0", "lodash@4.17.15"], "packageManager": "npm"},
+		{"id": "SNYK-JS-TYPESNODE-1", "title": "Denial of Service", "severity": "medium", "packageName": "@types/node", "version": "18.0.0", "from": ["sbom@1.0.0", "@types/node@18.0.0"], "packageManager": "npm"},
+		{"id": "SNYK-JAVA-JACKSON-1", "title": "Deserialization", "severity": "high", "packageName": "com.fasterxml.jackson.core:jackson-databind", "version": "2.9.8", "fixedIn": ["2.9.9"], "from": ["sbom@1.0.0", "com.fasterxml.jackson.core:jackson-databind@2.9.8"], "packageManager": "maven", "purl": "pkg:maven/com.fasterxml.jackson.core/jackson-databind@2.9.8?type=jar"}
+	]
+}`
+
+func TestConvertSbomJsonToIssues(t *testing.T) {
+	issues, components, err := ConvertSbomJsonToIssues("", []byte(sbomTestOutput), false)
+	require.NoError(t, err)
+
+	require.Len(t, issues, 4)
+	assert.Equal(t, "pkg:npm/lodash@4.17.15", issues[0].Purl)
+	assert.Equal(t, "Upgrade to lodash@4.17.19", issues[0].Remediation)
+	assert.Equal(t, "pkg:npm/%40types/node@18.0.0", issues[2].Purl)
+	assert.Equal(t, "pkg:maven/com.fasterxml.jackson.core/jackson-databind@2.9.8?type=jar", issues[3].Purl, "reported purl is preferred")
+
+	assert.Equal(t, []Component{
+		{
+			Name:            "lodash",
+			Version:         "4.17.15",
+			Ecosystem:       "npm",
+			Purl:            "pkg:npm/lodash@4.17.15",
+			HighestSeverity: "critical",
+			IssueCount:      2,
+			IssueIDs:        []string{"SNYK-JS-LODASH-1", "SNYK-JS-LODASH-2"},
+			FixedIn:         []string{"4.17.19", "4.17.21"},
+			Remediation:     "Upgrade to lodash@4.17.19",
+		},
+		{
+			Name:            "com.fasterxml.jackson.core:jackson-databind",
+			Version:         "2.9.8",
+			Ecosystem:       "maven",
+			Purl:            "pkg:maven/com.fasterxml.jackson.core/jackson-databind@2.9.8?type=jar",
+			HighestSeverity: "high",
+			IssueCount:      1,
+			IssueIDs:        []string{"SNYK-JAVA-JACKSON-1"},
+			FixedIn:         []string{"2.9.9"},
+		},
+		{
+			Name:            "@types/node",
+			Version:         "18.0.0",
+			Ecosystem:       "npm",
+			Purl:            "pkg:npm/%40types/node@18.0.0",
+			HighestSeverity: "medium",
+			IssueCount:      1,
+			IssueIDs:        []string{"SNYK-JS-TYPESNODE-1"},
+		},
+	}, components)
+}
+
+func TestConvertSbomJsonToIssuesWithoutVulnerabilities(t *testing.T) {
+	issues, components, err := ConvertSbomJsonToIssues("", []byte(`{"ok": true, "vulnerabilities": []}`), false)
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+	assert.Empty(t, components)
+
+	_, _, err = ConvertSbomJsonToIssues("", []byte(`{"ok":`), false)
+	assert.Error(t, err)
+}
+
+func TestBuildPurl(t *testing.T) {
+	testCases := []struct {
+		name      string
+		ecosystem string
+		pkg       string
+		version   string
+		expected  string
+	}{
+		{name: "npm", ecosystem: "npm", pkg: "lodash", version: "4.17.15", expected: "pkg:npm/lodash@4.17.15"},
+		{name: "scoped npm", ecosystem: "yarn", pkg: "@babel/core", version: "7.0.0", expected: "pkg:npm/%40babel/core@7.0.0"},
+		{name: "maven", ecosystem: "maven", pkg: "org.apache.logging.log4j:log4j-core", version: "2.14.1", expected: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"},
+		{name: "pypi", ecosystem: "pip", pkg: "django", version: "3.2", expected: "pkg:pypi/django@3.2"},
+		{name: "go module", ecosystem: "gomodules", pkg: "golang.org/x/net", version: "v0.1.0", expected: "pkg:golang/golang.org/x/net@v0.1.0"},
+		{name: "debian binary package", ecosystem: "deb", pkg: "openssl/libssl1.1", version: "1.1.1n-0+deb11u3", expected: "pkg:deb/libssl1.1@1.1.1n-0+deb11u3"},
+		{name: "without version", ecosystem: "npm", pkg: "lodash", expected: "pkg:npm/lodash"},
+		{name: "unknown ecosystem", ecosystem: "unknown", pkg: "lodash", version: "1.0.0", expected: ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, buildPurl(tc.ecosystem, tc.pkg, tc.version))
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package sbom
+
+// ScanResult holds the SBOM-specific parts of the `snyk sbom test --json` output.
+// The vulnerabilities share their schema with SCA scan results.
+type ScanResult struct {
+	PackageManager  string          `json:"packageManager"`
+	Vulnerabilities []vulnerability `json:"vulnerabilities"`
+}
+
+type vulnerability struct {
+	Id             string `json:"id"`
+	PackageName    string `json:"packageName"`
+	Version        string `json:"version"`
+	PackageManager string `json:"packageManager"`
+	Purl           string `json:"purl"`
+	PackageURL     string `json:"packageUrl"`
+}
+
+// Component is a component of the SBOM with the vulnerabilities found in it
+type Component struct {
+	Name            string   `json:"name"`
+	Version         string   `json:"version"`
+	Ecosystem       string   `json:"ecosystem,omitempty"`
+	Purl            string   `json:"purl,omitempty"`
+	HighestSeverity string   `json:"highestSeverity"`
+	IssueCount      int      `json:"issueCount"`
+	IssueIDs        []string `json:"issueIds"`
+	// FixedIn are the versions the issues of the component are fixed in
+	FixedIn     []string `json:"fixedIn,omitempty"`
+	Remediation string   `json:"remediation,omitempty"`
+}
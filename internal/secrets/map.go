@@ -0,0 +1,156 @@
+package secrets
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/snyk/studio-mcp/internal/types"
+)
+
+// Redacted replaces secret values in the output
+const Redacted = "[REDACTED]"
+
+// secretLikeToken matches long tokens that mix letters and digits, like API keys and passwords.
+// Slashes are left out so file paths are kept.
+var secretLikeToken = regexp.MustCompile(`[A-Za-z0-9+=_\-]{16,}`)
+
+// assignedValue matches values assigned to secret-looking names, e.g. `password: hunter2`
+var assignedValue = regexp.MustCompile(`(?i)((?:secret|password|passwd|pwd|token|api[_-]?key|access[_-]?key|private[_-]?key|credential)s?["']?\s*[:=]\s*["']?)([^\s"',;]+)`)
+
+var levelSeverities = map[string]string{
+	"error":   "high",
+	"warning": "medium",
+	"note":    "low",
+}
+
+// ConvertSarifJsonToIssues converts the SARIF output of `snyk secrets test` into issues.
+// Matched secret values are redacted from all text and code snippets are dropped, so they never reach the caller.
+func ConvertSarifJsonToIssues(workDir string, res []byte, includeIgnores bool) ([]types.IssueData, error) {
+	var log sarifLog
+	if err := json.Unmarshal(res, &log); err != nil {
+		return nil, fmt.Errorf("failed to parse SARIF JSON: %w", err)
+	}
+
+	issues := []types.IssueData{}
+	for _, run := range log.Runs {
+		for _, result := range run.Results {
+			isIgnored := isSuppressed(result)
+			if isIgnored && !includeIgnores {
+				continue
+			}
+			rule := getRule(run, result.RuleID)
+			for _, location := range result.Locations {
+				issues = append(issues, toIssue(workDir, result, rule, location, isIgnored))
+			}
+		}
+	}
+	return issues, nil
+}
+
+func toIssue(workDir string, result sarifResult, rule sarifRule, location sarifLocation, isIgnored bool) types.IssueData {
+	physicalLocation := location.PhysicalLocation
+	matchedValues := []string{physicalLocation.Region.Snippet.Text, physicalLocation.ContextRegion.Snippet.Text}
+	matchedValues = append(matchedValues, result.Message.Arguments...)
+
+	title := rule.ShortDescription.Text
+	if title == "" {
+		title = rule.Name
+	}
+	if title == "" {
+		title = result.RuleID
+	}
+
+	return types.IssueData{
+		ID:          result.RuleID,
+		Title:       RedactText(title, nil),
+		Severity:    severity(result, rule),
+		Message:     RedactText(result.Message.Text, matchedValues),
+		FilePath:    absolutePath(workDir, physicalLocation.ArtifactLocation.URI),
+		Line:        physicalLocation.Region.StartLine,
+		Column:      physicalLocation.Region.StartColumn,
+		FingerPrint: fingerprint(result),
+		IsIgnored:   isIgnored,
+	}
+}
+
+// RedactText replaces the matched secret values and anything that looks like a secret in the text
+func RedactText(text string, matchedValues []string) string {
+	for _, value := range matchedValues {
+		value = strings.TrimSpace(value)
+		if value != "" {
+			text = strings.ReplaceAll(text, value, Redacted)
+		}
+	}
+	text = assignedValue.ReplaceAllString(text, "${1}"+Redacted)
+	return secretLikeToken.ReplaceAllStringFunc(text, func(token string) string {
+		if strings.ContainsAny(token, "0123456789") && strings.IndexFunc(token, isLetter) >= 0 {
+			return Redacted
+		}
+		return token
+	})
+}
+
+func isLetter(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+func getRule(run sarifRun, id string) sarifRule {
+	for _, rule := range run.Tool.Driver.Rules {
+		if rule.ID == id {
+			return rule
+		}
+	}
+	return sarifRule{}
+}
+
+// severity prefers the Snyk severity of the result or rule over the SARIF level
+func severity(result sarifResult, rule sarifRule) string {
+	for _, s := range []string{result.Properties.Severity, rule.Properties.Severity} {
+		if s != "" {
+			return strings.ToLower(s)
+		}
+	}
+	if s, ok := levelSeverities[result.Level]; ok {
+		return s
+	}
+	return "medium"
+}
+
+// isSuppressed returns true if the result is ignored. Suppressions without status are accepted, as per SARIF.
+func isSuppressed(result sarifResult) bool {
+	return slices.ContainsFunc(result.Suppressions, func(suppression sarifSuppression) bool {
+		return suppression.Status == "" || strings.EqualFold(suppression.Status, "accepted")
+	})
+}
+
+// fingerprint returns a stable identifier of the finding
+func fingerprint(result sarifResult) string {
+	for _, fingerprints := range []map[string]string{result.Fingerprints, result.PartialFingerprints} {
+		keys := make([]string, 0, len(fingerprints))
+		for key := range fingerprints {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		if len(keys) > 0 {
+			return fingerprints[keys[0]]
+		}
+	}
+	return ""
+}
+
+func absolutePath(workDir string, uri string) string {
+	path := uri
+	if decoded, err := url.PathUnescape(strings.TrimPrefix(uri, "file://")); err == nil {
+		path = decoded
+	}
+	path = filepath.FromSlash(path)
+	if filepath.IsAbs(path) || workDir == "" {
+		return path
+	}
+	return filepath.Join(workDir, path)
+}
@@ -0,0 +1,66 @@
+package secrets
+
+// sarifLog is the subset of the SARIF 2.1.0 output of `snyk secrets test --sarif` that is mapped to issues
+type sarifLog struct {
+	Runs []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool struct {
+		Driver struct {
+			Rules []sarifRule `json:"rules"`
+		} `json:"driver"`
+	} `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	Name             string       `json:"name"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+	Properties       struct {
+		Severity string `json:"severity"`
+	} `json:"properties"`
+}
+
+type sarifResult struct {
+	RuleID              string             `json:"ruleId"`
+	Level               string             `json:"level"`
+	Message             sarifMessage       `json:"message"`
+	Locations           []sarifLocation    `json:"locations"`
+	Fingerprints        map[string]string  `json:"fingerprints"`
+	PartialFingerprints map[string]string  `json:"partialFingerprints"`
+	Suppressions        []sarifSuppression `json:"suppressions"`
+	Properties          struct {
+		Severity string `json:"severity"`
+	} `json:"properties"`
+}
+
+type sarifSuppression struct {
+	Kind   string `json:"kind"`
+	Status string `json:"status"`
+}
+
+type sarifMessage struct {
+	Text      string   `json:"text"`
+	Markdown  string   `json:"markdown"`
+	Arguments []string `json:"arguments"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			URI string `json:"uri"`
+		} `json:"artifactLocation"`
+		Region        sarifRegion `json:"region"`
+		ContextRegion sarifRegion `json:"contextRegion"`
+	} `json:"physicalLocation"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	Snippet     struct {
+		Text string `json:"text"`
+	} `json:"snippet"`
+}
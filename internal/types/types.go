@@ -1,6 +1,8 @@
 package types
 
 import (
+	"strings"
+
 	"github.com/pkg/browser"
 	"github.com/rs/zerolog/log"
 )
@@ -51,7 +53,11 @@ type IssueData struct {
 	Column      int               `json:"column,omitempty"`
 	Message     string            `json:"message,omitempty"`
 	FingerPrint string            `json:"fingerPrint,omitempty"`
-	IsIgnored   bool              `json:"isIgnored,omitempty"`
+	// PackageFingerPrint is set by SCA scans only. Unlike FingerPrint it
+	// doesn't depend on the dependency paths, it identifies the vulnerable
+	// package version in the target file across scans.
+	PackageFingerPrint string `json:"packageFingerPrint,omitempty"`
+	IsIgnored          bool   `json:"isIgnored,omitempty"`
 	// IsTransitiveDependency is set by SCA scans only. Pointer + omitempty
 	// preserves false in SCA payloads while keeping the key absent from non-SCA
 	// payloads.
@@ -60,8 +66,37 @@ type IssueData struct {
 	// to the vulnerable package: ordered from[1:] entries (project root
 	// excluded). Nil or empty omits the JSON key (omitempty).
 	IntroducedThrough []string `json:"introducedThrough,omitempty"`
+	// DependencyPaths is set by SCA scans if the vulnerable package is
+	// introduced through more than one path: each entry is a dependency
+	// chain from the direct dependency to the vulnerable package.
+	DependencyPaths [][]string `json:"dependencyPaths,omitempty"`
+	// ResourcePath is set by IaC scans only: the path to the misconfigured
+	// attribute within the file, e.g. `resource.aws_s3_bucket[logs].acl`.
+	ResourcePath string `json:"resourcePath,omitempty"`
+	// Impact is set by IaC scans only and describes the consequence of the
+	// misconfiguration.
+	Impact string `json:"impact,omitempty"`
+	// Origin is set by container scans only: `base-image` and `dockerfile`
+	// for OS package vulnerabilities from the base image or from an
+	// instruction in the Dockerfile, `os` if that is unknown because no
+	// Dockerfile was given, and `application` for application dependencies.
+	Origin string `json:"origin,omitempty"`
+	// DockerfileInstruction is the Dockerfile instruction that introduced the
+	// vulnerable package, set by container scans with a Dockerfile only.
+	DockerfileInstruction string `json:"dockerfileInstruction,omitempty"`
+	// Type and License are set by SCA scans for license policy violations
+	// only: Type is `license` and License is the license ID, e.g. `GPL-3.0`.
+	// The severity and the remediation are those of the org's license policy.
+	Type    string `json:"type,omitempty"`
+	License string `json:"license,omitempty"`
+	// Purl is the package URL of the vulnerable component, set by SBOM scans
+	// only.
+	Purl string `json:"purl,omitempty"`
 }
 
+// LicenseIssueType is the Type of issues that violate the license policy of the org
+const LicenseIssueType = "license"
+
 var IssuesSeverity = map[string]Severity{
 	"critical": Critical,
 	"high":     High,
@@ -92,3 +127,11 @@ func (s Severity) String() string {
 		return "unknown"
 	}
 }
+
+// SeverityRank orders severities from critical (0) to low, unknown severities last
+func SeverityRank(severity string) int {
+	if rank, ok := IssuesSeverity[strings.ToLower(severity)]; ok {
+		return int(rank)
+	}
+	return len(IssuesSeverity)
+}
@@ -0,0 +1,70 @@
+package upgrade
+
+import (
+	"fmt"
+	"strings"
+)
+
+const diffContextLines = 3
+
+const noNewlineMarker = "\\ No newline at end of file\n"
+
+// unifiedDiff returns the unified diff of a manifest. Upgrades replace versions within lines,
+// so the lines of the old and new content correspond one to one.
+func unifiedDiff(name string, oldContent []byte, newContent []byte) string {
+	oldLines := strings.SplitAfter(string(oldContent), "\n")
+	newLines := strings.SplitAfter(string(newContent), "\n")
+	if len(oldLines) != len(newLines) {
+		return ""
+	}
+
+	var changed []int
+	for i := range oldLines {
+		if oldLines[i] != newLines[i] {
+			changed = append(changed, i)
+		}
+	}
+	if len(changed) == 0 {
+		return ""
+	}
+
+	var diff strings.Builder
+	fmt.Fprintf(&diff, "--- a/%s\n+++ b/%s\n", name, name)
+	for start := 0; start < len(changed); {
+		// a hunk covers the changed lines whose context overlaps
+		end := start
+		for end+1 < len(changed) && changed[end+1]-changed[end] <= 2*diffContextLines {
+			end++
+		}
+		first := max(changed[start]-diffContextLines, 0)
+		last := min(changed[end]+diffContextLines, lastLine(oldLines))
+		fmt.Fprintf(&diff, "@@ -%d,%d +%d,%d @@\n", first+1, last-first+1, first+1, last-first+1)
+		for i := first; i <= last; i++ {
+			if oldLines[i] == newLines[i] {
+				writeDiffLine(&diff, " ", oldLines[i])
+				continue
+			}
+			writeDiffLine(&diff, "-", oldLines[i])
+			writeDiffLine(&diff, "+", newLines[i])
+		}
+		start = end + 1
+	}
+	return diff.String()
+}
+
+// lastLine returns the index of the last line, SplitAfter returns an empty line after a trailing newline
+func lastLine(lines []string) int {
+	if lines[len(lines)-1] == "" {
+		return len(lines) - 2
+	}
+	return len(lines) - 1
+}
+
+func writeDiffLine(diff *strings.Builder, prefix string, line string) {
+	diff.WriteString(prefix)
+	diff.WriteString(line)
+	if !strings.HasSuffix(line, "\n") {
+		diff.WriteString("\n")
+		diff.WriteString(noNewlineMarker)
+	}
+}
@@ -0,0 +1,46 @@
+package upgrade
+
+import (
+	"regexp"
+	"strings"
+)
+
+// goRequirement matches a requirement in a require block, or a single-line require directive
+var goRequirement = regexp.MustCompile(`^\s*(?:require\s+)?(\S+)\s+(v\S+)`)
+
+func editGoMod(content []byte, packageName string, version string) (*edit, error) {
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+
+	e := &edit{}
+	inRequireBlock := false
+	offset := 0
+	for _, line := range strings.SplitAfter(string(content), "\n") {
+		lineStart := offset
+		offset += len(line)
+		directive := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(directive, "require") && strings.HasSuffix(directive, "("):
+			inRequireBlock = true
+			continue
+		case inRequireBlock && strings.HasPrefix(directive, ")"):
+			inRequireBlock = false
+			continue
+		case !inRequireBlock && !strings.HasPrefix(directive, "require"):
+			continue
+		}
+
+		match := goRequirement.FindStringSubmatchIndex(line)
+		// indirect requirements are not direct dependencies of the module
+		if match == nil || line[match[2]:match[3]] != packageName || strings.Contains(line[match[1]:], "// indirect") {
+			continue
+		}
+		e.fromVersion = line[match[4]:match[5]]
+		e.replacements = append(e.replacements, replacement{start: lineStart + match[4], end: lineStart + match[5], text: version})
+	}
+	if len(e.replacements) == 0 {
+		return nil, errNotDeclared
+	}
+	return e, nil
+}
@@ -0,0 +1,80 @@
+package upgrade
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	pomDependency = regexp.MustCompile(`(?s)<dependency>.*?</dependency>`)
+	pomGroupID    = regexp.MustCompile(`<groupId>\s*([^<]*?)\s*</groupId>`)
+	pomArtifactID = regexp.MustCompile(`<artifactId>\s*([^<]*?)\s*</artifactId>`)
+	pomVersion    = regexp.MustCompile(`<version>\s*([^<]*?)\s*</version>`)
+	pomProperty   = regexp.MustCompile(`^\$\{([^}]+)\}$`)
+)
+
+// editPom upgrades a Maven dependency, named groupId:artifactId as in Snyk issues.
+// Versions defined by a property are upgraded in the property.
+func editPom(content []byte, packageName string, version string) (*edit, error) {
+	groupID, artifactID, hasGroup := strings.Cut(packageName, ":")
+	if !hasGroup {
+		groupID, artifactID = "", packageName
+	}
+
+	text := string(content)
+	e := &edit{}
+	replaced := map[int]bool{}
+	declared := false
+	for _, location := range pomDependency.FindAllStringIndex(text, -1) {
+		dependency := text[location[0]:location[1]]
+		artifact := pomArtifactID.FindStringSubmatch(dependency)
+		if artifact == nil || artifact[1] != artifactID {
+			continue
+		}
+		if group := pomGroupID.FindStringSubmatch(dependency); groupID != "" && (group == nil || group[1] != groupID) {
+			continue
+		}
+		declared = true
+
+		versionLocation := pomVersion.FindStringSubmatchIndex(dependency)
+		if versionLocation == nil {
+			// managed by a parent or BOM
+			continue
+		}
+		start, end := location[0]+versionLocation[2], location[0]+versionLocation[3]
+		if property := pomProperty.FindStringSubmatch(text[start:end]); property != nil {
+			var err error
+			start, end, err = pomPropertyRange(text, property[1])
+			if err != nil {
+				return nil, err
+			}
+		}
+		if replaced[start] {
+			continue
+		}
+		replaced[start] = true
+		if e.fromVersion == "" {
+			e.fromVersion = text[start:end]
+		}
+		e.replacements = append(e.replacements, replacement{start: start, end: end, text: version})
+	}
+
+	switch {
+	case !declared:
+		return nil, errNotDeclared
+	case len(e.replacements) == 0:
+		return nil, fmt.Errorf("the version of %s is managed by a parent or BOM", packageName)
+	}
+	return e, nil
+}
+
+// pomPropertyRange returns the range of a property value
+func pomPropertyRange(text string, name string) (int, int, error) {
+	property := regexp.MustCompile(`<` + regexp.QuoteMeta(name) + `>\s*([^<]*?)\s*</` + regexp.QuoteMeta(name) + `>`)
+	location := property.FindStringSubmatchIndex(text)
+	if location == nil {
+		return 0, 0, fmt.Errorf("property %s is not defined in this pom.xml", name)
+	}
+	return location[2], location[3], nil
+}
@@ -0,0 +1,114 @@
+package upgrade
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// npmDependencySections are the sections of package.json that declare direct dependencies
+var npmDependencySections = []string{"dependencies", "devDependencies", "optionalDependencies", "peerDependencies"}
+
+// npmVersionSpec matches exact versions and caret or tilde ranges, which keep their operator on upgrade
+var npmVersionSpec = regexp.MustCompile(`^([\^~]?)v?(\d+(?:\.\d+){0,2}(?:[-+][0-9A-Za-z.+-]*)?)$`)
+
+func editPackageJSON(content []byte, packageName string, version string) (*edit, error) {
+	var manifest map[string]json.RawMessage
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid package.json: %w", err)
+	}
+
+	text := string(content)
+	e := &edit{}
+	for _, section := range npmDependencySections {
+		var dependencies map[string]string
+		if raw, ok := manifest[section]; !ok || json.Unmarshal(raw, &dependencies) != nil {
+			continue
+		}
+		spec, ok := dependencies[packageName]
+		if !ok {
+			continue
+		}
+		match := npmVersionSpec.FindStringSubmatch(spec)
+		if match == nil {
+			return nil, fmt.Errorf("unsupported version range %q in %s", spec, section)
+		}
+
+		start, end, found := jsonObjectRange(text, section)
+		if !found {
+			return nil, fmt.Errorf("%s section not found", section)
+		}
+		entry := regexp.MustCompile(`"` + regexp.QuoteMeta(packageName) + `"\s*:\s*"(` + regexp.QuoteMeta(spec) + `)"`)
+		location := entry.FindStringSubmatchIndex(text[start:end])
+		if location == nil {
+			return nil, fmt.Errorf("%s entry of %s not found", section, packageName)
+		}
+		if e.fromVersion == "" {
+			e.fromVersion = match[2]
+		}
+		e.replacements = append(e.replacements, replacement{start: start + location[2], end: start + location[3], text: match[1] + version})
+	}
+	if len(e.replacements) == 0 {
+		return nil, errNotDeclared
+	}
+	return e, nil
+}
+
+// jsonObjectRange returns the range of the object value of a top-level key
+func jsonObjectRange(text string, key string) (int, int, bool) {
+	depth := 0
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case '"':
+			end := jsonStringEnd(text, i)
+			if depth == 1 && text[i+1:end] == key {
+				rest := strings.TrimLeft(text[end+1:], " \t\r\n")
+				if strings.HasPrefix(rest, ":") {
+					valueStart := len(text) - len(strings.TrimLeft(rest[1:], " \t\r\n"))
+					if valueStart < len(text) && text[valueStart] == '{' {
+						return valueStart, jsonObjectEnd(text, valueStart), true
+					}
+				}
+			}
+			i = end
+		}
+	}
+	return 0, 0, false
+}
+
+// jsonObjectEnd returns the index after the closing brace of the object starting at start
+func jsonObjectEnd(text string, start int) int {
+	depth := 0
+	for i := start; i < len(text); i++ {
+		switch text[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		case '"':
+			i = jsonStringEnd(text, i)
+		}
+	}
+	return len(text)
+}
+
+// jsonStringEnd returns the index of the closing quote of the string starting at start
+func jsonStringEnd(text string, start int) int {
+	for i := start + 1; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return len(text) - 1
+}
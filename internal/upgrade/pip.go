@@ -0,0 +1,61 @@
+package upgrade
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// requirementLine matches the name, extras and first version clause of a requirement
+var requirementLine = regexp.MustCompile(`^\s*([A-Za-z0-9][A-Za-z0-9._-]*)\s*(\[[^\]]*\])?\s*(?:(===|==|~=|>=|<=|!=|<|>)\s*([^\s,;#]+))?`)
+
+// pythonNameSeparators are treated as equal in package names, see PEP 503
+var pythonNameSeparators = regexp.MustCompile(`[-_.]+`)
+
+func normalizePythonName(name string) string {
+	return strings.ToLower(pythonNameSeparators.ReplaceAllString(name, "-"))
+}
+
+func editRequirements(content []byte, packageName string, version string) (*edit, error) {
+	text := string(content)
+	e := &edit{}
+	offset := 0
+	for _, line := range strings.SplitAfter(text, "\n") {
+		lineStart := offset
+		offset += len(line)
+		if strings.HasPrefix(strings.TrimSpace(line), "-") {
+			// options like -r or --index-url
+			continue
+		}
+		match := requirementLine.FindStringSubmatchIndex(line)
+		if match == nil || normalizePythonName(line[match[2]:match[3]]) != normalizePythonName(packageName) {
+			continue
+		}
+
+		if match[6] < 0 && strings.HasPrefix(strings.TrimSpace(line[match[1]:]), "@") {
+			return nil, fmt.Errorf("unsupported direct reference in %q", strings.TrimSpace(line))
+		}
+		if match[6] < 0 {
+			// an unpinned requirement is pinned after the name and extras
+			nameEnd := match[3]
+			if match[4] >= 0 {
+				nameEnd = match[5]
+			}
+			e.replacements = append(e.replacements, replacement{start: lineStart + nameEnd, end: lineStart + nameEnd, text: "==" + version})
+			continue
+		}
+		operator := line[match[6]:match[7]]
+		rest := strings.TrimSpace(line[match[1]:])
+		if operator != "==" && operator != "===" && operator != "~=" && operator != ">=" || strings.HasPrefix(rest, ",") {
+			return nil, fmt.Errorf("unsupported version specifier in %q", strings.TrimSpace(line))
+		}
+		if e.fromVersion == "" {
+			e.fromVersion = line[match[8]:match[9]]
+		}
+		e.replacements = append(e.replacements, replacement{start: lineStart + match[8], end: lineStart + match[9], text: version})
+	}
+	if len(e.replacements) == 0 {
+		return nil, errNotDeclared
+	}
+	return e, nil
+}
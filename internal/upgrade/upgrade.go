@@ -0,0 +1,134 @@
+package upgrade
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ManifestFiles are the manifests in which direct dependencies are upgraded, in the order they are searched in a directory
+var ManifestFiles = []string{"package.json", "requirements.txt", "pom.xml", "go.mod"}
+
+// errNotDeclared is returned by editors if the manifest doesn't declare the package
+var errNotDeclared = errors.New("package is not declared")
+
+// Upgrade is the bump of a direct dependency in a manifest file. It is written with Apply.
+type Upgrade struct {
+	ManifestFile string `json:"manifestFile"`
+	PackageName  string `json:"packageName"`
+	// FromVersion is the version declared before the upgrade, without range operators
+	FromVersion string `json:"fromVersion"`
+	ToVersion   string `json:"toVersion"`
+	Diff        string `json:"diff"`
+	content     []byte
+}
+
+// replacement replaces content[start:end] with text
+type replacement struct {
+	start int
+	end   int
+	text  string
+}
+
+// edit is the result of an editor. fromVersion is the declared version without range operators.
+type edit struct {
+	fromVersion  string
+	replacements []replacement
+}
+
+type editor func(content []byte, packageName string, version string) (*edit, error)
+
+func editorFor(manifestFile string) (editor, bool) {
+	name := filepath.Base(manifestFile)
+	switch {
+	case name == "package.json":
+		return editPackageJSON, true
+	case name == "pom.xml":
+		return editPom, true
+	case name == "go.mod":
+		return editGoMod, true
+	case strings.HasPrefix(name, "requirements") && strings.HasSuffix(name, ".txt"):
+		return editRequirements, true
+	}
+	return nil, false
+}
+
+// Plan prepares the upgrade of a package to a version. path is a manifest file, or a project directory
+// whose first manifest that declares the package is upgraded. Versions are replaced within their lines,
+// so the formatting of the manifest is kept.
+func Plan(path string, packageName string, version string) (*Upgrade, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return planManifest(path, filepath.Base(path), packageName, version)
+	}
+
+	for _, manifest := range ManifestFiles {
+		manifestFile := filepath.Join(path, manifest)
+		if _, statErr := os.Stat(manifestFile); statErr != nil {
+			continue
+		}
+		upgrade, planErr := planManifest(manifestFile, manifest, packageName, version)
+		if errors.Is(planErr, errNotDeclared) {
+			continue
+		}
+		return upgrade, planErr
+	}
+	return nil, fmt.Errorf("%s is not a direct dependency in the manifests of %s, supported manifests are %s", packageName, path, strings.Join(ManifestFiles, ", "))
+}
+
+func planManifest(manifestFile string, displayName string, packageName string, version string) (*Upgrade, error) {
+	editManifest, ok := editorFor(manifestFile)
+	if !ok {
+		return nil, fmt.Errorf("unsupported manifest %s, supported manifests are %s", filepath.Base(manifestFile), strings.Join(ManifestFiles, ", "))
+	}
+	content, err := os.ReadFile(manifestFile)
+	if err != nil {
+		return nil, err
+	}
+	e, err := editManifest(content, packageName, version)
+	if errors.Is(err, errNotDeclared) {
+		return nil, fmt.Errorf("%s is not a direct dependency in %s: %w", packageName, manifestFile, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot upgrade %s in %s: %w", packageName, manifestFile, err)
+	}
+
+	upgraded := applyReplacements(content, e.replacements)
+	if string(upgraded) == string(content) {
+		return nil, fmt.Errorf("%s is already at version %s in %s", packageName, version, manifestFile)
+	}
+	return &Upgrade{
+		ManifestFile: manifestFile,
+		PackageName:  packageName,
+		FromVersion:  e.fromVersion,
+		ToVersion:    version,
+		Diff:         unifiedDiff(filepath.ToSlash(displayName), content, upgraded),
+		content:      upgraded,
+	}, nil
+}
+
+// Apply writes the upgraded manifest
+func (u *Upgrade) Apply() error {
+	info, err := os.Stat(u.ManifestFile)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(u.ManifestFile, u.content, info.Mode().Perm())
+}
+
+func applyReplacements(content []byte, replacements []replacement) []byte {
+	sorted := append([]replacement{}, replacements...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].start > sorted[j].start })
+
+	result := string(content)
+	for _, r := range sorted {
+		result = result[:r.start] + r.text + result[r.end:]
+	}
+	return []byte(result)
+}
@@ -0,0 +1,268 @@
+package upgrade
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeManifest(t *testing.T, dir string, name string, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func upgradedContent(t *testing.T, edit editor, content string, packageName string, version string) string {
+	t.Helper()
+	e, err := edit([]byte(content), packageName, version)
+	require.NoError(t, err)
+	return string(applyReplacements([]byte(content), e.replacements))
+}
+
+func TestEditPackageJSON(t *testing.T) {
+	manifest := `{
+    "name": "app",
+    "description": "dependencies of lodash",
+    "dependencies": {
+        "express":   "^4.17.1",
+        "lodash": "4.17.15"
+    },
+    "devDependencies": { "lodash": "~4.17.15", "jest": "29.0.0" },
+    "overrides": { "lodash": "4.17.15" }
+}
+`
+
+	t.Run("keeps range operators and formatting", func(t *testing.T) {
+		upgraded := upgradedContent(t, editPackageJSON, manifest, "lodash", "4.17.21")
+
+		assert.Contains(t, upgraded, `"lodash": "4.17.21"`)
+		assert.Contains(t, upgraded, `{ "lodash": "~4.17.21", "jest": "29.0.0" }`)
+		assert.Contains(t, upgraded, `"overrides": { "lodash": "4.17.15" }`, "only dependency sections are upgraded")
+		assert.Contains(t, upgraded, `"express":   "^4.17.1"`)
+	})
+
+	t.Run("from version", func(t *testing.T) {
+		e, err := editPackageJSON([]byte(manifest), "express", "4.21.2")
+		require.NoError(t, err)
+		assert.Equal(t, "4.17.1", e.fromVersion)
+	})
+
+	t.Run("not declared", func(t *testing.T) {
+		_, err := editPackageJSON([]byte(manifest), "react", "18.0.0")
+		assert.ErrorIs(t, err, errNotDeclared)
+	})
+
+	t.Run("unsupported range", func(t *testing.T) {
+		_, err := editPackageJSON([]byte(`{"dependencies": {"lodash": ">=4 <5"}}`), "lodash", "4.17.21")
+		assert.ErrorContains(t, err, "unsupported version range")
+	})
+}
+
+func TestEditRequirements(t *testing.T) {
+	requirements := `# production dependencies
+-r base.txt
+Django==3.2.1 # LTS
+requests[security] >= 2.25.0 ; python_version > "3.6"
+pyyaml
+urllib3>=1.26,<2
+flask-login @ git+https://github.com/maxcountryman/flask-login.git
+`
+
+	t.Run("pinned", func(t *testing.T) {
+		upgraded := upgradedContent(t, editRequirements, requirements, "django", "3.2.25")
+		assert.Contains(t, upgraded, "Django==3.2.25 # LTS\n")
+	})
+
+	t.Run("minimum version with extras and markers", func(t *testing.T) {
+		upgraded := upgradedContent(t, editRequirements, requirements, "requests", "2.32.0")
+		assert.Contains(t, upgraded, "requests[security] >= 2.32.0 ; python_version > \"3.6\"\n")
+	})
+
+	t.Run("unpinned", func(t *testing.T) {
+		upgraded := upgradedContent(t, editRequirements, requirements, "PyYAML", "6.0.1")
+		assert.Contains(t, upgraded, "\npyyaml==6.0.1\n")
+	})
+
+	t.Run("multiple clauses", func(t *testing.T) {
+		_, err := editRequirements([]byte(requirements), "urllib3", "1.26.18")
+		assert.ErrorContains(t, err, "unsupported version specifier")
+	})
+
+	t.Run("direct reference", func(t *testing.T) {
+		_, err := editRequirements([]byte(requirements), "flask_login", "0.6.3")
+		assert.ErrorContains(t, err, "unsupported direct reference")
+	})
+
+	t.Run("not declared", func(t *testing.T) {
+		_, err := editRequirements([]byte(requirements), "flask", "3.0.0")
+		assert.ErrorIs(t, err, errNotDeclared)
+	})
+}
+
+func TestEditPom(t *testing.T) {
+	pom := `<project>
+  <properties>
+    <jackson.version>2.12.1</jackson.version>
+  </properties>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>com.fasterxml.jackson.core</groupId>
+        <artifactId>jackson-databind</artifactId>
+        <version>${jackson.version}</version>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+  <dependencies>
+    <dependency>
+      <groupId>com.fasterxml.jackson.core</groupId>
+      <artifactId>jackson-databind</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>org.apache.commons</groupId>
+      <artifactId>commons-text</artifactId>
+      <version>1.9</version>
+    </dependency>
+    <dependency>
+      <groupId>org.springframework</groupId>
+      <artifactId>spring-core</artifactId>
+    </dependency>
+  </dependencies>
+</project>
+`
+
+	t.Run("literal version", func(t *testing.T) {
+		upgraded := upgradedContent(t, editPom, pom, "org.apache.commons:commons-text", "1.10.0")
+		assert.Contains(t, upgraded, "<artifactId>commons-text</artifactId>\n      <version>1.10.0</version>")
+	})
+
+	t.Run("property version", func(t *testing.T) {
+		e, err := editPom([]byte(pom), "com.fasterxml.jackson.core:jackson-databind", "2.12.7.1")
+		require.NoError(t, err)
+		assert.Equal(t, "2.12.1", e.fromVersion)
+		upgraded := string(applyReplacements([]byte(pom), e.replacements))
+		assert.Contains(t, upgraded, "<jackson.version>2.12.7.1</jackson.version>")
+		assert.Contains(t, upgraded, "<version>${jackson.version}</version>")
+	})
+
+	t.Run("managed version", func(t *testing.T) {
+		_, err := editPom([]byte(pom), "org.springframework:spring-core", "5.3.30")
+		assert.ErrorContains(t, err, "managed by a parent or BOM")
+	})
+
+	t.Run("other group", func(t *testing.T) {
+		_, err := editPom([]byte(pom), "org.other:commons-text", "1.10.0")
+		assert.ErrorIs(t, err, errNotDeclared)
+	})
+}
+
+func TestEditGoMod(t *testing.T) {
+	goMod := `module example.com/app
+
+go 1.22
+
+require github.com/gin-gonic/gin v1.9.0
+
+require (
+	golang.org/x/net v0.17.0 // indirect
+	gopkg.in/yaml.v3 v3.0.0
+)
+
+replace golang.org/x/net v0.17.0 => ./net
+`
+
+	upgraded := upgradedContent(t, editGoMod, goMod, "gopkg.in/yaml.v3", "3.0.1")
+	assert.Contains(t, upgraded, "\tgopkg.in/yaml.v3 v3.0.1\n")
+
+	upgraded = upgradedContent(t, editGoMod, goMod, "github.com/gin-gonic/gin", "v1.9.1")
+	assert.Contains(t, upgraded, "require github.com/gin-gonic/gin v1.9.1\n")
+
+	_, err := editGoMod([]byte(goMod), "golang.org/x/net", "v0.23.0")
+	assert.ErrorIs(t, err, errNotDeclared, "indirect requirements are not upgraded")
+
+	_, err = editGoMod([]byte(goMod), "github.com/other/module", "v1.0.0")
+	assert.ErrorIs(t, err, errNotDeclared)
+}
+
+func TestPlan(t *testing.T) {
+	projectDir := t.TempDir()
+	writeManifest(t, projectDir, "package.json", "{\n  \"dependencies\": {\n    \"express\": \"^4.17.1\"\n  }\n}\n")
+	requirementsFile := writeManifest(t, projectDir, "requirements.txt", "a==1\nb==1\nc==1\nd==1\ne==1\nf==1\ng==1\nh==1\ndjango==3.2.1")
+
+	t.Run("finds the manifest of the package in a directory", func(t *testing.T) {
+		upgrade, err := Plan(projectDir, "django", "3.2.25")
+		require.NoError(t, err)
+
+		assert.Equal(t, requirementsFile, upgrade.ManifestFile)
+		assert.Equal(t, "3.2.1", upgrade.FromVersion)
+		assert.Equal(t, `--- a/requirements.txt
++++ b/requirements.txt
+@@ -6,4 +6,4 @@
+ f==1
+ g==1
+ h==1
+-django==3.2.1
+\ No newline at end of file
++django==3.2.25
+\ No newline at end of file
+`, upgrade.Diff)
+
+		before, err := os.ReadFile(requirementsFile)
+		require.NoError(t, err)
+		assert.Contains(t, string(before), "django==3.2.1", "nothing is written before Apply")
+
+		require.NoError(t, upgrade.Apply())
+		after, err := os.ReadFile(requirementsFile)
+		require.NoError(t, err)
+		assert.Contains(t, string(after), "django==3.2.25")
+	})
+
+	t.Run("manifest file", func(t *testing.T) {
+		upgrade, err := Plan(filepath.Join(projectDir, "package.json"), "express", "4.21.2")
+		require.NoError(t, err)
+
+		assert.Equal(t, "--- a/package.json\n+++ b/package.json\n@@ -1,5 +1,5 @@\n {\n   \"dependencies\": {\n-    \"express\": \"^4.17.1\"\n+    \"express\": \"^4.21.2\"\n   }\n }\n", upgrade.Diff)
+	})
+
+	t.Run("already upgraded", func(t *testing.T) {
+		_, err := Plan(projectDir, "express", "4.17.1")
+		assert.ErrorContains(t, err, "already at version")
+	})
+
+	t.Run("not a direct dependency", func(t *testing.T) {
+		_, err := Plan(projectDir, "minimist", "1.2.8")
+		assert.ErrorContains(t, err, "not a direct dependency")
+	})
+
+	t.Run("unsupported manifest", func(t *testing.T) {
+		_, err := Plan(writeManifest(t, projectDir, "build.gradle", ""), "junit", "4.13.2")
+		assert.ErrorContains(t, err, "unsupported manifest")
+	})
+}
+
+func TestUnifiedDiffHunks(t *testing.T) {
+	oldContent := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n"
+	newContent := "1\nX\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\nY\n15\n"
+
+	assert.Equal(t, `--- a/f
++++ b/f
+@@ -1,5 +1,5 @@
+ 1
+-2
++X
+ 3
+ 4
+ 5
+@@ -11,5 +11,5 @@
+ 11
+ 12
+ 13
+-14
++Y
+ 15
+`, unifiedDiff("f", []byte(oldContent), []byte(newContent)))
+}
@@ -42,13 +42,17 @@ var WORKFLOWID_MCP_CONFIG = workflow.NewWorkflowIdentifier("mcp.configure")
 
 func Init(engine workflow.Engine) error {
 	mcpFlags := pflag.NewFlagSet("mcp", pflag.ContinueOnError)
-	mcpFlags.StringP(mcp.TransportParam, "t", "sse", "sets transport to <sse|stdio>")
+	mcpFlags.StringP(mcp.TransportParam, "t", "sse", "sets transport to <sse|stdio|http>")
 
 	mcpFlags.Bool(configuration.FLAG_EXPERIMENTAL, false, "enable experimental mcp command")
 	_ = mcpFlags.MarkDeprecated(configuration.FLAG_EXPERIMENTAL, "This is feature is in early access.")
 
 	mcpFlags.Bool(trust.DisableTrustFlag, false, "disable folder trust")
 	mcpFlags.StringP(shared.OutputDirParam, "o", "", "specifies the output directory for scan responses")
+	mcpFlags.String(shared.AuthTokenParam, "", "bearer token HTTP clients must present when using the sse or http transport (can also be set via "+shared.AuthTokenEnvVar+")")
+	mcpFlags.Bool(shared.GenerateAuthTokenParam, false, "generate a random per-launch bearer token for the sse or http transport and write it to a file only the current user can read")
+	mcpFlags.Int(shared.MaxCliProcessesParam, mcp.DefaultMaxCliProcesses, "maximum number of Snyk CLI processes that run at the same time. identical concurrent scans share one process")
+	mcpFlags.Int(shared.ToolTimeoutParam, mcp.DefaultToolTimeoutSeconds, "time in seconds after which a tool run and its processes are stopped, unless the tool defines its own timeout. 0 disables the timeout")
 	mcpFlags.StringP(mcp.ProfileFlagName, "p", "", "sets the tool profile <lite|full|experimental>. 'full' (default) includes all non-experimental tools, 'lite' includes essential tools only, 'experimental' includes all tools")
 
 	configureFlags := pflag.NewFlagSet("configure", pflag.ContinueOnError)
@@ -59,6 +63,9 @@ func Init(engine workflow.Engine) error {
 	configureFlags.Bool(shared.RemoveParam, false, "remove the Snyk MCP server from the specified tool configuration")
 	configureFlags.Bool(shared.ConfigureMcpParam, true, "configure MCP server in tool's config file (default true)")
 	configureFlags.Bool(shared.ConfigureRulesParam, true, "configure Snyk rules for the tool (default true)")
+	configureFlags.String(shared.TransportParam, "stdio", "transport the tool connects to the MCP server with <stdio|sse|http>. sse and http connect to a running `snyk mcp -t <sse|http>`")
+	configureFlags.String(shared.ServerUrlParam, "", "url of the running sse or http MCP server. defaults to the default loopback port")
+	configureFlags.String(shared.AuthTokenParam, "", "bearer token of the running sse or http MCP server to write into the tool's configuration (can also be set via "+shared.AuthTokenEnvVar+")")
 
 	mcpCfg := workflow.ConfigurationOptionsFromFlagset(mcpFlags)
 	mcpEntry, _ := engine.Register(WORKFLOWID_MCP, mcpCfg, mcpWorkflow)
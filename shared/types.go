@@ -13,6 +13,8 @@ const (
 	RemoveParam              = "rm"
 	ConfigureMcpParam        = "configure-mcp"   // Flag to enable/disable MCP server configuration
 	ConfigureRulesParam      = "configure-rules" // Flag to enable/disable rules configuration
+	TransportParam           = "transport"       // Transport the tool connects to the MCP server with: stdio (default), sse or http
+	ServerUrlParam           = "url"             // URL of a running sse or http MCP server, defaults to the default loopback port
 
 	RulesGlobalScope    = "global"
 	RulesWorkspaceScope = "workspace"
@@ -25,9 +27,14 @@ const (
 )
 
 const (
-	TrustedFoldersParam        = "trusted-folders"
-	IdeConfigPathParam         = "ide-config-path"
-	OutputDirParam      string = "output-dir"
+	TrustedFoldersParam           = "trusted-folders"
+	IdeConfigPathParam            = "ide-config-path"
+	OutputDirParam         string = "output-dir"
+	AuthTokenParam                = "auth-token"          // Bearer token HTTP clients must present to the SSE/HTTP listener
+	GenerateAuthTokenParam        = "generate-auth-token" // Generate a random per-launch bearer token for the SSE/HTTP listener
+	AuthTokenEnvVar               = "SNYK_MCP_AUTH_TOKEN"
+	MaxCliProcessesParam          = "max-cli-processes" // Maximum number of concurrently running CLI processes
+	ToolTimeoutParam              = "tool-timeout"      // Default time in seconds after which a tool run is stopped, 0 disables
 )
 
 type McpRegisterCallback func(cmd string, args []string, env map[string]string) error